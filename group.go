@@ -0,0 +1,74 @@
+package spec
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// groups tracks the state of each group in a suite while the suite runs.
+// Groups are identified by location, since all closures are re-evaluated
+// for each spec.
+type groups map[string]*group
+
+type group struct {
+	mu        sync.Mutex
+	started   bool
+	failed    bool
+	remaining int
+}
+
+func newGroups(n node, hasFocus bool) groups {
+	gs := groups{}
+	n.specs(func(spec node) {
+		if spec.skip(hasFocus) {
+			return
+		}
+		for i := range spec.loc {
+			g := gs.get(spec.loc[:i])
+			if g == nil {
+				g = &group{}
+				gs[groupKey(spec.loc[:i])] = g
+			}
+			g.remaining++
+		}
+	})
+	return gs
+}
+
+func (gs groups) get(loc []int) *group {
+	return gs[groupKey(loc)]
+}
+
+func groupKey(loc []int) string {
+	return fmt.Sprint(loc)
+}
+
+// start runs the provided BeforeAll hooks if no other spec in the group has
+// started. Concurrent specs in the group wait for the hooks to complete.
+// It returns false if the hooks failed when another spec ran them.
+func (g *group) start(t *testing.T, fs ...func()) bool {
+	t.Helper()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		return !g.failed
+	}
+	g.started = true
+	g.failed = true
+	run(t, fs...)
+	g.failed = t.Failed()
+	return true
+}
+
+// end runs the provided AfterAll hooks if every other spec in the group has
+// completed and the BeforeAll hooks for the group have run.
+func (g *group) end(t *testing.T, fs ...func()) {
+	t.Helper()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.remaining--
+	if g.remaining == 0 && g.started {
+		run(t, fs...)
+	}
+}
@@ -0,0 +1,169 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const envLabels = "SPEC_LABELS"
+
+type filter func(labels []string) bool
+
+func newFilter(exprs ...string) (filter, error) {
+	var fs []filter
+	for _, expr := range exprs {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		f, err := parseFilter(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", expr, err)
+		}
+		fs = append(fs, f)
+	}
+	return func(labels []string) bool {
+		for _, f := range fs {
+			if !f(labels) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseFilter(expr string) (filter, error) {
+	p := &filterParser{tokens: tokenize(expr)}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != "" {
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+	return f, nil
+}
+
+type filterParser struct {
+	tokens []string
+}
+
+func (p *filterParser) peek() string {
+	if len(p.tokens) == 0 {
+		return ""
+	}
+	return p.tokens[0]
+}
+
+func (p *filterParser) next() string {
+	tok := p.peek()
+	if tok != "" {
+		p.tokens = p.tokens[1:]
+	}
+	return tok
+}
+
+func (p *filterParser) or() (filter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(labels []string) bool { return l(labels) || right(labels) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) and() (filter, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(labels []string) bool { return l(labels) && right(labels) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) not() (filter, error) {
+	switch tok := p.next(); tok {
+	case "":
+		return nil, errors.New("unexpected end of expression")
+	case "!":
+		f, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(labels []string) bool { return !f(labels) }, nil
+	case "(":
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing )")
+		}
+		return f, nil
+	case "&&", "||", "&", "|", ")":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		return func(labels []string) bool {
+			for _, l := range labels {
+				if l == tok {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+}
+
+func tokenize(expr string) []string {
+	var tokens []string
+	for rest := expr; rest != ""; {
+		switch {
+		case unicode.IsSpace(rune(rest[0])):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "&&"), strings.HasPrefix(rest, "||"):
+			tokens, rest = append(tokens, rest[:2]), rest[2:]
+		case strings.ContainsRune("!()&|", rune(rest[0])):
+			tokens, rest = append(tokens, rest[:1]), rest[1:]
+		default:
+			end := strings.IndexFunc(rest, func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune("!()&|", r)
+			})
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens, rest = append(tokens, rest[:end]), rest[end:]
+		}
+	}
+	return tokens
+}
+
+func mergeLabels(labels ...[]string) []string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, ls := range labels {
+		for _, l := range ls {
+			if !seen[l] {
+				seen[l] = true
+				merged = append(merged, l)
+			}
+		}
+	}
+	return merged
+}
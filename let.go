@@ -0,0 +1,61 @@
+package spec
+
+import (
+	"context"
+	"sync"
+)
+
+// A Lazy is a value that is computed the first time it is used in each spec.
+// Lazy values are defined with Let.
+type Lazy[T any] struct {
+	it    S
+	mu    sync.Mutex
+	f     func() T
+	ctx   context.Context
+	value T
+}
+
+// Let defines a value that is computed by f the first time Get is called in
+// each spec, and then reused for the rest of the spec.
+// The value is tied to the spec that is running when Get is called, as
+// identified by it.Context, so it is never shared between specs, even if the
+// Lazy is used by specs that run in parallel.
+// Get must only be called from within specs and hooks, and it panics if it is
+// called while the suite is being defined.
+//
+// Example:
+//
+//	db := spec.Let(it, func() *DB { return openDB() })
+//	it.After(func() { db.Get().Close() })
+//	it("queries", func() { db.Get().Query() })
+//	when("read-only", func() {
+//		db.Let(func() *DB { return openReadOnlyDB() })
+//		it("fails to write", func() { db.Get().Write() })
+//	})
+func Let[T any](it S, f func() T) *Lazy[T] {
+	return &Lazy[T]{it: it, f: f}
+}
+
+// Get returns the value, computing it if this is the first call in the spec.
+func (l *Lazy[T]) Get() T {
+	ctx := l.it.Context()
+	if ctx == nil {
+		panic("spec: Lazy.Get called outside of a spec")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ctx != ctx {
+		l.value = l.f()
+		l.ctx = ctx
+	}
+	return l.value
+}
+
+// Let overrides the function used to compute the value.
+// When called within a group, it only applies to specs in that group.
+func (l *Lazy[T]) Let(f func() T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var zero T
+	l.f, l.ctx, l.value = f, nil, zero
+}
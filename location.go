@@ -0,0 +1,43 @@
+package spec
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// A Location is the file and line where a spec or group is defined.
+type Location struct {
+	File string
+	Line int
+}
+
+func (l Location) String() string {
+	if l.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// pkgDir is the directory that contains the source of this package.
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(file)
+}()
+
+// caller returns the location of the first caller outside of this package.
+// Test files are considered to be outside of this package.
+func caller() Location {
+	pc := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+	for {
+		frame, more := frames.Next()
+		if path.Dir(frame.File) != pkgDir || strings.HasSuffix(frame.File, "_test.go") {
+			return Location{File: frame.File, Line: frame.Line}
+		}
+		if !more {
+			return Location{}
+		}
+	}
+}
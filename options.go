@@ -1,8 +1,14 @@
 package spec
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 // An Option controls the behavior of a suite, group, or spec.
@@ -23,6 +29,7 @@ type Option func(*config)
 // New, Run, Focus, Pend
 func Report(r Reporter) Option {
 	return func(c *config) {
+		c.valid("Report", targetRun)
 		c.report = r
 	}
 }
@@ -35,6 +42,7 @@ func Report(r Reporter) Option {
 // New, Run, Focus, Pend
 func Seed(s int64) Option {
 	return func(c *config) {
+		c.valid("Seed", targetRun)
 		c.seed = s
 	}
 }
@@ -46,6 +54,7 @@ func Seed(s int64) Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Sequential() Option {
 	return func(c *config) {
+		c.valid("Sequential", targetRun|targetGroup)
 		c.order = orderSequential
 	}
 }
@@ -57,6 +66,7 @@ func Sequential() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Random() Option {
 	return func(c *config) {
+		c.valid("Random", targetRun|targetGroup)
 		c.order = orderRandom
 	}
 }
@@ -67,6 +77,7 @@ func Random() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Reverse() Option {
 	return func(c *config) {
+		c.valid("Reverse", targetRun|targetGroup)
 		c.order = orderReverse
 	}
 }
@@ -78,6 +89,7 @@ func Reverse() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend, S
 func Parallel() Option {
 	return func(c *config) {
+		c.valid("Parallel", targetRun|targetGroup|targetSpec)
 		c.order = orderParallel
 	}
 }
@@ -92,6 +104,7 @@ func Parallel() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Local() Option {
 	return func(c *config) {
+		c.valid("Local", targetRun|targetGroup)
 		c.scope = scopeLocal
 	}
 }
@@ -105,6 +118,7 @@ func Local() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Global() Option {
 	return func(c *config) {
+		c.valid("Global", targetRun|targetGroup)
 		c.scope = scopeGlobal
 	}
 }
@@ -116,6 +130,7 @@ func Global() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Flat() Option {
 	return func(c *config) {
+		c.valid("Flat", targetRun|targetGroup)
 		c.nest = nestOff
 	}
 }
@@ -127,10 +142,105 @@ func Flat() Option {
 // New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend
 func Nested() Option {
 	return func(c *config) {
+		c.valid("Nested", targetRun|targetGroup)
 		c.nest = nestOn
 	}
 }
 
+// Labels attaches labels to a spec or group of specs.
+// Labels are inherited by subgroups and subspecs, such that each spec has
+// every label of every group that contains it.
+// Labels are provided to the Reporter and may be used to skip specs with Filter.
+//
+// Valid Option for:
+// New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend, S, S.Focus
+func Labels(labels ...string) Option {
+	return func(c *config) {
+		c.valid("Labels", targetRun|targetGroup|targetSpec)
+		c.labels = append(c.labels, labels...)
+	}
+}
+
+// Filter skips every spec in a suite with labels that do not match the
+// provided expression. Expressions consist of labels combined with
+// && (and), || (or), ! (not), and parentheses.
+// For example, "integration && !slow" runs all specs labeled integration
+// that are not also labeled slow.
+// If the SPEC_LABELS environment variable is set, specs must also match the
+// expression it contains.
+//
+// Valid Option for:
+// New, Run, Focus, Pend
+func Filter(expr string) Option {
+	return func(c *config) {
+		c.valid("Filter", targetRun)
+		c.filter = expr
+	}
+}
+
+// Timeout fails a spec if the spec and its hooks take longer than the
+// provided duration to run. When a spec times out, it is failed immediately,
+// the running spec or hook is abandoned, and the remaining After hooks,
+// deferred functions, and AfterAll hooks run without a timeout.
+// Timeout applies to each spec individually, not to the group as a whole.
+// BeforeAll, AfterAll, and Around hooks are not subject to the timeout, and
+// the time they take does not count toward it.
+//
+// An abandoned spec or hook continues to run in the background, and it must
+// not use t after the spec completes, or the test binary will panic.
+// Long-running specs should watch S.Context, which is cancelled when the
+// timeout expires, and return immediately.
+//
+// Valid Option for:
+// New, Run, Focus, Pend, Suite, Suite.Focus, Suite.Pend, G, G.Focus, G.Pend, S, S.Focus
+func Timeout(d time.Duration) Option {
+	return func(c *config) {
+		c.valid("Timeout", targetRun|targetGroup|targetSpec)
+		c.timeout = d
+	}
+}
+
+// FailOnFocus fails the suite after it runs if any specs are focused.
+// This prevents focused specs from skipping the rest of the suite unnoticed.
+// Setting the SPEC_FAIL_ON_FOCUS environment variable to true has the same
+// effect, which is useful in CI.
+//
+// Valid Option for:
+// New, Run, Focus, Pend
+func FailOnFocus() Option {
+	return func(c *config) {
+		c.valid("FailOnFocus", targetRun)
+		c.failOnFocus = true
+	}
+}
+
+const envFailOnFocus = "SPEC_FAIL_ON_FOCUS"
+
+func envBool(key string) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	return v && err == nil
+}
+
+type target int
+
+const (
+	targetRun target = 1 << iota
+	targetGroup
+	targetSpec
+)
+
+func (t target) String() string {
+	switch t {
+	case targetRun:
+		return "suite"
+	case targetGroup:
+		return "group"
+	case targetSpec:
+		return "spec"
+	}
+	return "unknown"
+}
+
 type order int
 
 const (
@@ -141,6 +251,20 @@ const (
 	orderReverse
 )
 
+func (o order) String() string {
+	switch o {
+	case orderSequential:
+		return "sequential"
+	case orderParallel:
+		return "parallel"
+	case orderRandom:
+		return "random"
+	case orderReverse:
+		return "reverse"
+	}
+	return "inherit"
+}
+
 func (o order) or(last order) order {
 	return order(defaultZero(int(o), int(last)))
 }
@@ -183,18 +307,69 @@ func defaultZero64(next, last int64) int64 {
 	return next
 }
 
+func defaultZeroDuration(next, last time.Duration) time.Duration {
+	return time.Duration(defaultZero64(int64(next), int64(last)))
+}
+
 type config struct {
-	seed   int64
-	order  order
-	scope  scope
-	nest   nest
-	pend   bool
-	focus  bool
-	before bool
-	after  bool
-	t      *testing.T
-	out    func(io.Writer)
-	report Reporter
+	seed        int64
+	order       order
+	scope       scope
+	nest        nest
+	pend        bool
+	focus       bool
+	labels      []string
+	filter      string
+	failOnFocus bool
+	timeout     time.Duration
+	before      bool
+	after       bool
+	justBefore  bool
+	deferred    bool
+	beforeAll   bool
+	afterAll    bool
+	around      func(func())
+	t           *testing.T
+	location    Location
+	out         func(io.Writer)
+	ctx         func(context.Context)
+	report      Reporter
+	usage       []usage
+}
+
+type usage struct {
+	name    string
+	targets target
+}
+
+func (c *config) valid(name string, targets target) {
+	c.usage = append(c.usage, usage{name, targets})
+}
+
+func (c *config) validate(t target, path []string) error {
+	var invalid []string
+	for _, u := range c.usage {
+		if u.targets&t == 0 {
+			invalid = append(invalid, u.name)
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return fmt.Errorf("Invalid Option %s for %s: %s",
+		strings.Join(invalid, ", "), t, strings.Join(path, "/"))
+}
+
+func (c *config) hook() bool {
+	return c.before || c.after || c.justBefore || c.deferred ||
+		c.beforeAll || c.afterAll || c.around != nil
+}
+
+// locate records the location of the caller, unless a location is provided.
+func (c *config) locate() {
+	if c.location.File == "" {
+		c.location = caller()
+	}
 }
 
 type options []Option
@@ -3,9 +3,13 @@ package spec_test
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"reflect"
 	"regexp"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sclevine/spec"
 )
@@ -111,7 +115,11 @@ func TestReport(t *testing.T) {
 	if reporter.SpecsT != t {
 		t.Fatal("Incorrect value for t on spec run.")
 	}
-	if reporter.StartPlan != (spec.Plan{
+	if nodes := reporter.StartPlan.Nodes; len(nodes) != 1 || nodes[0].Text != "Top" {
+		t.Fatal("Incorrect nodes:", nodes)
+	}
+	reporter.StartPlan.Nodes = nil
+	if !reflect.DeepEqual(reporter.StartPlan, spec.Plan{
 		Text:      "Suite",
 		Total:     18,
 		Pending:   0,
@@ -135,8 +143,21 @@ func TestReport(t *testing.T) {
 	if string(empty) != "" || err != nil {
 		t.Fatal("Incorrect output for empty buffer.")
 	}
-	for i := range reporter.SpecOrder {
+	for i, s := range reporter.SpecOrder {
+		if s.Start.IsZero() || s.Duration < s.BeforeDuration+s.BodyDuration+s.AfterDuration {
+			t.Fatal("Incorrect timing for:", s.Text)
+		}
+		if s.Location.File == "" || len(s.Index) == 0 {
+			t.Fatal("Incorrect location for:", s.Text)
+		}
 		reporter.SpecOrder[i].Out = nil
+		reporter.SpecOrder[i].Start = time.Time{}
+		reporter.SpecOrder[i].Duration = 0
+		reporter.SpecOrder[i].BeforeDuration = 0
+		reporter.SpecOrder[i].BodyDuration = 0
+		reporter.SpecOrder[i].AfterDuration = 0
+		reporter.SpecOrder[i].Location = spec.Location{}
+		reporter.SpecOrder[i].Index = nil
 	}
 
 	if !reflect.DeepEqual(reporter.SpecOrder, []spec.Spec{
@@ -160,6 +181,116 @@ func TestReport(t *testing.T) {
 	}
 }
 
+func TestReportTiming(t *testing.T) {
+	reporter := &testReporter{}
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.BeforeAll(func() { time.Sleep(50 * time.Millisecond) })
+		it.AfterAll(func() { time.Sleep(50 * time.Millisecond) })
+		it.Before(func() { time.Sleep(10 * time.Millisecond) })
+		it.After(func() { time.Sleep(20 * time.Millisecond) })
+		it("Run.S", func() { time.Sleep(30 * time.Millisecond) })
+		it.Pend("Run.S.Pend", func() {})
+	}, spec.Report(reporter))
+
+	ran, pending := reporter.SpecOrder[0], reporter.SpecOrder[1]
+	if ran.Start.IsZero() ||
+		ran.BeforeDuration < 10*time.Millisecond ||
+		ran.BodyDuration < 30*time.Millisecond ||
+		ran.AfterDuration < 20*time.Millisecond ||
+		ran.BeforeDuration >= 50*time.Millisecond ||
+		ran.AfterDuration >= 50*time.Millisecond ||
+		ran.Duration < ran.BeforeDuration+ran.BodyDuration+ran.AfterDuration+100*time.Millisecond {
+		t.Fatal("Incorrect timing for spec:", ran)
+	}
+	if !pending.Start.IsZero() || pending.Duration != 0 {
+		t.Fatal("Incorrect timing for pending spec:", pending)
+	}
+}
+
+func TestReportLocation(t *testing.T) {
+	reporter := &testReporter{}
+
+	suite := spec.New("Suite", spec.Report(reporter))
+	_, file, line, _ := runtime.Caller(0)
+	suite("Top", func(t *testing.T, when spec.G, it spec.S) {
+		it("Top.S", func() {})
+		spec.Each(it, "Top.S.%d", []int{1}, func(int) {})
+	})
+	suite.Before(func(*testing.T) {})
+	suite.Run(t)
+
+	if nodes := reporter.StartPlan.Nodes; nodes[0].Location != (spec.Location{File: file, Line: line + 1}) {
+		t.Fatal("Incorrect suite location:", nodes[0].Location)
+	}
+
+	var locations []spec.Location
+	for _, s := range reporter.SpecOrder {
+		locations = append(locations, s.Location)
+	}
+	if !reflect.DeepEqual(locations, []spec.Location{
+		{File: file, Line: line + 2},
+		{File: file, Line: line + 3},
+	}) {
+		t.Fatal("Incorrect locations:", locations)
+	}
+}
+
+func TestReportNodes(t *testing.T) {
+	reporter := &testReporter{}
+
+	_, file, line, _ := runtime.Caller(0)
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it("S.2", func() {}, spec.Labels("slow"))
+		when("G", func() {
+			it.Focus("G.S", func() {}, spec.Parallel())
+			when.Pend("G.G", func() {
+				it("G.G.S", func() {})
+			})
+		}, spec.Random(), spec.Global(), spec.Nested())
+		it("S.1", func() {})
+	}, spec.Reverse(), spec.Filter("!slow"), spec.Seed(1), spec.Report(reporter))
+
+	if !reflect.DeepEqual(reporter.StartPlan.Nodes, []spec.Node{
+		{
+			Text: "S.2", Location: spec.Location{File: file, Line: line + 2},
+			Order: "reverse", Excluded: true, Labels: []string{"slow"}, Index: []int{0},
+		},
+		{
+			Text: "G", Location: spec.Location{File: file, Line: line + 3},
+			Order: "random", Global: true, Nested: true, Index: []int{1},
+			Nodes: []spec.Node{
+				{
+					Text: "G.S", Location: spec.Location{File: file, Line: line + 4},
+					Order: "parallel", Global: true, Nested: true, Focused: true, Index: []int{1, 0},
+				},
+				{
+					Text: "G.G", Location: spec.Location{File: file, Line: line + 5},
+					Order: "random", Global: true, Nested: true, Pending: true, Index: []int{1, 1},
+					Nodes: []spec.Node{{
+						Text: "G.G.S", Location: spec.Location{File: file, Line: line + 6},
+						Order: "random", Global: true, Nested: true, Pending: true, Index: []int{1, 1, 0},
+					}},
+				},
+			},
+		},
+		{
+			Text: "S.1", Location: spec.Location{File: file, Line: line + 9},
+			Order: "reverse", Index: []int{2},
+		},
+	}) {
+		t.Fatal("Incorrect nodes:", reporter.StartPlan.Nodes)
+	}
+
+	var indexes [][]int
+	for _, s := range reporter.SpecOrder {
+		indexes = append(indexes, s.Index)
+	}
+	if !reflect.DeepEqual(indexes, [][]int{{2}, {1, 1, 0}, {1, 0}, {0}}) {
+		t.Fatal("Incorrect indexes:", indexes)
+	}
+}
+
 func TestDefault(t *testing.T) {
 	s, calls := record(t)
 
@@ -355,3 +486,286 @@ func TestFlat(t *testing.T) {
 		t.Fatal("Incorrect order:", calls())
 	}
 }
+
+func labelTestCases(t *testing.T, when spec.G, it spec.S, s recorder) {
+	it("S", s(t, "S"))
+	it("S.Unit", s(t, "S.Unit"), spec.Labels("unit"))
+	when("G.Integration", func() {
+		it("G.Integration.S", s(t, "G.Integration.S"))
+		it("G.Integration.S.Slow", s(t, "G.Integration.S.Slow"), spec.Labels("slow"))
+	}, spec.Labels("integration"))
+}
+
+func TestLabels(t *testing.T) {
+	reporter := &testReporter{}
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		labelTestCases(t, when, it, func(*testing.T, string) func() { return func() {} })
+	}, spec.Labels("all"), spec.Report(reporter))
+
+	if !reflect.DeepEqual(reporter.StartPlan.Labels, []string{"all", "integration", "slow", "unit"}) {
+		t.Fatal("Incorrect plan labels:", reporter.StartPlan.Labels)
+	}
+	var labels [][]string
+	for _, s := range reporter.SpecOrder {
+		labels = append(labels, s.Labels)
+	}
+	if !reflect.DeepEqual(labels, [][]string{
+		{"all"},
+		{"all", "unit"},
+		{"all", "integration"},
+		{"all", "integration", "slow"},
+	}) {
+		t.Fatal("Incorrect spec labels:", labels)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	for expr, expected := range map[string][]string{
+		"":                       {"Run/S->S", "Run/S.Unit->S.Unit", "Run/G.Integration/G.Integration.S->G.Integration.S", "Run/G.Integration/G.Integration.S.Slow->G.Integration.S.Slow"},
+		"unit":                   {"Run/S.Unit->S.Unit"},
+		"integration && !slow":   {"Run/G.Integration/G.Integration.S->G.Integration.S"},
+		"!(integration || unit)": {"Run/S->S"},
+		"unit || slow":           {"Run/S.Unit->S.Unit", "Run/G.Integration/G.Integration.S.Slow->G.Integration.S.Slow"},
+	} {
+		s, calls := record(t)
+		reporter := &testReporter{}
+
+		spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			labelTestCases(t, when, it, s)
+		}, spec.Filter(expr), spec.Report(reporter))
+
+		results := calls()
+		for i := range results {
+			results[i] = regexp.MustCompile(`#[0-9]+`).ReplaceAllLiteralString(results[i], "")
+		}
+		if !reflect.DeepEqual(results, expected) {
+			t.Fatal("Incorrect calls for:", expr, results)
+		}
+		if reporter.StartPlan.Excluded != 4-len(expected) {
+			t.Fatal("Incorrect excluded count for:", expr, reporter.StartPlan.Excluded)
+		}
+	}
+}
+
+func TestFilterEnv(t *testing.T) {
+	s, calls := record(t)
+
+	defer os.Setenv("SPEC_LABELS", os.Getenv("SPEC_LABELS"))
+	os.Setenv("SPEC_LABELS", "integration")
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		labelTestCases(t, when, it, s)
+	}, spec.Filter("!slow"))
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/G.Integration/G.Integration.S->G.Integration.S",
+	}) {
+		t.Fatal("Incorrect calls:", calls())
+	}
+}
+
+func TestFailOnFocus(t *testing.T) {
+	if inSubprocess(t) {
+		fmt.Println("Option returned:", spec.Run(t, "Option", func(t *testing.T, when spec.G, it spec.S) {
+			it("S", func() {})
+			it.Focus("S.Focus", func() {})
+		}, spec.FailOnFocus()))
+		fmt.Println("Env returned:", spec.Run(t, "Env", func(t *testing.T, when spec.G, it spec.S) {
+			it("S", func() {})
+			it.Focus("S.Focus", func() {})
+		}))
+		fmt.Println("Unfocused returned:", spec.Run(t, "Unfocused", func(t *testing.T, when spec.G, it spec.S) {
+			it("S", func() {})
+		}, spec.FailOnFocus()))
+		return
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	optionFocus := fmt.Sprintf("%s:%d: Option/S.Focus\n", file, line-12)
+	envFocus := fmt.Sprintf("%s:%d: Env/S.Focus\n", file, line-8)
+
+	out := subprocess(t, "SPEC_FAIL_ON_FOCUS=false")
+	if !strings.Contains(out, "Option returned: false\n") ||
+		!strings.Contains(out, "Env returned: true\n") ||
+		!strings.Contains(out, "Unfocused returned: true\n") ||
+		strings.Count(out, "Focus is not permitted, but the suite contains focused specs:") != 1 ||
+		!strings.Contains(out, optionFocus) || strings.Contains(out, envFocus) {
+		t.Fatal("Incorrect output:", out)
+	}
+
+	out = subprocess(t, "SPEC_FAIL_ON_FOCUS=true")
+	if !strings.Contains(out, "Option returned: false\n") ||
+		!strings.Contains(out, "Env returned: false\n") ||
+		!strings.Contains(out, "Unfocused returned: true\n") ||
+		strings.Count(out, "Focus is not permitted, but the suite contains focused specs:") != 2 ||
+		!strings.Contains(out, optionFocus) || !strings.Contains(out, envFocus) {
+		t.Fatal("Incorrect output:", out)
+	}
+}
+
+func TestFailOnFocusLocations(t *testing.T) {
+	if inSubprocess(t) {
+		spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			it.Focus("S.Focus", func() {})
+			it.Focus("S.Focus.Excluded", func() {}, spec.Labels("excluded"))
+			when.Focus("G.Focus", func() {
+				it("G.Focus.S", func() {})
+				it.Focus("G.Focus.S.Focus", func() {})
+			})
+			when.Pend("G.Pend", func() {
+				it.Focus("G.Pend.S.Focus", func() {})
+			})
+		}, spec.FailOnFocus(), spec.Filter("!excluded"))
+		return
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	out := subprocess(t)
+	for _, focus := range []string{
+		fmt.Sprintf("%s:%d: Run/S.Focus\n", file, line-13),
+		fmt.Sprintf("%s:%d: Run/G.Focus\n", file, line-11),
+		fmt.Sprintf("%s:%d: Run/G.Focus/G.Focus.S.Focus\n", file, line-9),
+	} {
+		if !strings.Contains(out, focus) {
+			t.Fatalf("Missing focused spec %q:\n%s", focus, out)
+		}
+	}
+	if strings.Contains(out, ": Run/S.Focus.Excluded\n") ||
+		strings.Contains(out, ": Run/G.Focus/G.Focus.S\n") ||
+		strings.Contains(out, ": Run/G.Pend/G.Pend.S.Focus\n") {
+		t.Fatal("Incorrect focused specs:", out)
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	if inSubprocess(t) {
+		fmt.Println("Run returned:", spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			it("S", func() { fmt.Println("S ran.") }, spec.Parallel(), spec.Labels("label"))
+			it("S.Invalid", func() {}, spec.Nested(), spec.Random())
+			when("G", func() {
+				it("G.S.Invalid", func() {}, spec.Seed(1))
+			}, spec.Global(), spec.Labels("label"))
+			when("G.Invalid", func() {}, spec.Report(nil), spec.Filter("label"))
+		}))
+		return
+	}
+
+	out := subprocess(t)
+	for _, re := range []string{
+		`(?m)^ +options_test.go:[0-9]+: Invalid Option Nested, Random for spec: Run/S.Invalid$`,
+		`(?m)^ +options_test.go:[0-9]+: Invalid Option Seed for spec: Run/G/G.S.Invalid$`,
+		`(?m)^ +options_test.go:[0-9]+: Invalid Option Report, Filter for group: Run/G.Invalid$`,
+		`(?m)^Run returned: false$`,
+	} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Fatalf("Output does not match %s:\n%s", re, out)
+		}
+	}
+	if strings.Contains(out, "S ran.") || strings.Count(out, "Invalid Option") != 3 {
+		t.Fatal("Incorrect output:", out)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s, calls := record(t)
+
+	suite := spec.New("Suite", spec.Seed(2), spec.Timeout(time.Minute))
+	suite("Top", func(t *testing.T, when spec.G, it spec.S) {
+		optionTestCases(t, when, it, s)
+	})
+	suite.Run(t)
+
+	if !reflect.DeepEqual(calls(), optionDefaultOrder(t, "Suite/Top", 2)) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestTimeoutExpired(t *testing.T) {
+	if inSubprocess(t) {
+		spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			it.After(func() { fmt.Println("After:", t.Name()) })
+			it.AfterAll(func() { fmt.Println("AfterAll") })
+			it("S.Hang", func() {
+				it.Defer(func() { fmt.Println("Defer:", t.Name()) })
+				<-it.Context().Done()
+				time.Sleep(10 * time.Millisecond)
+				panic("abandoned")
+			}, spec.Timeout(10*time.Millisecond))
+			it("S.Next", func() {
+				time.Sleep(20 * time.Millisecond)
+			}, spec.Timeout(time.Minute))
+			when("G.Around", func() {
+				it.Around(func(run func()) { run() })
+				it("S.Hang", func() {
+					<-it.Context().Done()
+				}, spec.Timeout(10*time.Millisecond))
+			})
+		})
+		return
+	}
+
+	out := subprocess(t)
+	for _, re := range []string{
+		`(?m)^ +options_test.go:[0-9]+: Spec TestTimeoutExpired/Run/S.Hang timed out after [0-9.]+ms\.$`,
+		`(?m)^After: TestTimeoutExpired/Run/S.Hang\nDefer: TestTimeoutExpired/Run/S.Hang$`,
+		`(?m)^After: TestTimeoutExpired/Run/G.Around/S.Hang\nAfterAll$`,
+		`(?m)^ +--- FAIL: TestTimeoutExpired/Run/S.Hang `,
+		`(?m)^ +--- PASS: TestTimeoutExpired/Run/S.Next `,
+		`(?m)^ +options_test.go:[0-9]+: Spec TestTimeoutExpired/Run/G.Around/S.Hang timed out after [0-9.]+ms\.$`,
+		`(?m)^ +--- FAIL: TestTimeoutExpired/Run/G.Around/S.Hang `,
+	} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Fatalf("Output does not match %s:\n%s", re, out)
+		}
+	}
+	if strings.Contains(out, "spec.go:") || strings.Contains(out, "panic") || strings.Contains(out, "DATA RACE") {
+		t.Fatal("Abandoned spec failed:", out)
+	}
+}
+
+func TestTimeoutUntimedHooks(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.BeforeAll(func() { time.Sleep(100 * time.Millisecond) })
+		it.AfterAll(func() { time.Sleep(100 * time.Millisecond) })
+		it.Around(func(run func()) {
+			time.Sleep(50 * time.Millisecond)
+			run()
+			time.Sleep(50 * time.Millisecond)
+		})
+		it.Before(func() { time.Sleep(10 * time.Millisecond) })
+		it("Run.S", func() {
+			deadline, ok := it.Context().Deadline()
+			if !ok || time.Until(deadline) > 40*time.Millisecond {
+				t.Error("Incorrect deadline:", deadline)
+			}
+			s(t, "Run.S")()
+		}, spec.Timeout(50*time.Millisecond))
+	})
+
+	if !reflect.DeepEqual(calls(), []string{"Run/Run.S->Run.S"}) {
+		t.Fatal("Incorrect calls:", calls())
+	}
+}
+
+func TestTimeoutSkip(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.Before(func() {
+			s(t, "Run.Before")()
+			t.SkipNow()
+		})
+		it.After(s(t, "Run.After"))
+		it("Run.S", s(t, "Run.S"), spec.Timeout(time.Minute))
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/Run.S->Run.Before",
+		"Run/Run.S->Run.After",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
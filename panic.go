@@ -0,0 +1,134 @@
+package spec
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A Panic describes a panic that occurred while a spec ran.
+// The spec fails, but the rest of its hooks and the rest of the suite run.
+//
+// Phase is the block that panicked: "G", "BeforeAll", "Before", "JustBefore",
+// "Around", "S", "After", "Defer", or "AfterAll".
+// Level is the nesting level of the group that defines the block, where zero
+// is the top level of the suite. For "S" and "Defer", Level is the nesting
+// level of the spec.
+// Stack is the stack trace of the panic, excluding frames from this package.
+type Panic struct {
+	Value any
+	Stack string
+	Phase string
+	Level int
+}
+
+type panics struct {
+	mu       sync.Mutex
+	first    *Panic
+	failures *failures
+}
+
+// guard runs f and fails the spec if f panics.
+func (p *panics) guard(t *testing.T, phase string, level int, f func()) {
+	t.Helper()
+	defer p.recover(t, phase, level)
+	f()
+}
+
+// recover fails the spec if it is deferred by a panicking function,
+// recording the first panic in the spec.
+func (p *panics) recover(t *testing.T, phase string, level int) {
+	t.Helper()
+	v := recover()
+	if v == nil {
+		return
+	}
+	pv := &Panic{
+		Value: v,
+		Stack: trimStack(string(debug.Stack())),
+		Phase: phase,
+		Level: level,
+	}
+	p.mu.Lock()
+	if p.first == nil {
+		p.first = pv
+	}
+	p.mu.Unlock()
+	p.failures.fatal(t, fmt.Sprintf("Panic in %s (level %d): %v\n%s", phase, level, v, pv.Stack))
+}
+
+// recovered returns an error describing a panic that occurred while parsing
+// the group at the provided path, or nil if v is nil.
+func recovered(v any, path []string) error {
+	if v == nil {
+		return nil
+	}
+	return fmt.Errorf("Panic in %s: %v\n%s",
+		strings.Join(path, "/"), v, trimStack(string(debug.Stack())))
+}
+
+func (p *panics) get() *Panic {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.first
+}
+
+// failures records the failures that this package reports for a spec.
+// Hooks and specs may run in goroutines other than the test goroutine, where
+// the testing package would report this package as the location of the
+// failure. Instead, failures are reported by the test goroutine.
+type failures struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+// errorf records a failure and marks the spec as failed.
+func (f *failures) errorf(t *testing.T, format string, args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.msgs = append(f.msgs, fmt.Sprintf(format, args...))
+	t.Fail()
+}
+
+// fatal records a failure and stops the spec, like t.Fatal.
+func (f *failures) fatal(t *testing.T, msg string) {
+	f.errorf(t, "%s", msg)
+	t.FailNow()
+}
+
+// report reports the recorded failures. It must be called by the test
+// goroutine after the spec completes.
+func (f *failures) report(t *testing.T) {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, msg := range f.msgs {
+		t.Error(msg)
+	}
+}
+
+// trimStack removes the frames that recover the most recent panic from a stack
+// trace, as well as the frames that call into the panicking function from this
+// package or the testing package.
+func trimStack(stack string) string {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	start := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			start = i + 2
+			break
+		}
+	}
+	var trimmed []string
+	for i := start; i+1 < len(lines); i += 2 {
+		fn := lines[i]
+		if strings.HasPrefix(fn, "github.com/sclevine/spec.") ||
+			strings.HasPrefix(fn, "testing.") {
+			break
+		}
+		trimmed = append(trimmed, fn, lines[i+1])
+	}
+	return strings.Join(trimmed, "\n")
+}
@@ -2,65 +2,111 @@ package spec
 
 import (
 	"math/rand"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 type node struct {
-	text  []string
-	loc   []int
-	seed  int64
-	order order
-	scope scope
-	nest  nest
-	pend  bool
-	focus bool
-	nodes tree
+	text     []string
+	loc      []int
+	location Location
+	seed     int64
+	order    order
+	scope    scope
+	nest     nest
+	pend     bool
+	focus    bool
+	exclude  bool
+	labels   []string
+	timeout  time.Duration
+	nodes    tree
 }
 
-func (n *node) parse(f func(*testing.T, G, S)) Plan {
-	// TODO: validate Options
-	plan := Plan{
+func (n *node) parse(f func(*testing.T, G, S), match filter) (plan Plan, focused []string, errs []error) {
+	plan = Plan{
 		Text: strings.Join(n.text, "/"),
 		Seed: n.seed,
 	}
+	if n.focus && !n.pend {
+		focused = append(focused, n.location.String()+": "+plan.Text)
+	}
+	path := n.text
+	nodes := &plan.Nodes
+	defer func() {
+		if err := recovered(recover(), path); err != nil {
+			errs = append(errs, err)
+		}
+	}()
 	f(nil, func(text string, f func(), opts ...Option) {
 		cfg := options(opts).apply()
+		cfg.locate()
+		parentPath := path
+		path = append(append([]string(nil), path...), text)
+		if err := cfg.validate(targetGroup, path); err != nil {
+			errs = append(errs, err)
+		}
 		n.add(text, cfg, tree{})
-		parent := n
+		parent, parentNodes := n, nodes
 		n = n.last()
 		plan.update(n)
+		*nodes = append(*nodes, n.describe(text))
+		nodes = &(*nodes)[len(*nodes)-1].Nodes
+		if cfg.focus && !n.pend {
+			focused = append(focused, n.location.String()+": "+strings.Join(path, "/"))
+		}
 		defer func() {
+			if err := recovered(recover(), path); err != nil {
+				errs = append(errs, err)
+			}
 			n.level()
 			n.sort()
 			n = parent
+			nodes = parentNodes
+			path = parentPath
 		}()
 		f()
 	}, func(text string, _ func(), opts ...Option) {
 		cfg := options(opts).apply()
-		if cfg.before || cfg.after || cfg.out != nil {
+		if cfg.hook() || cfg.out != nil || cfg.ctx != nil {
 			return
 		}
+		cfg.locate()
+		if err := cfg.validate(targetSpec, append(path, text)); err != nil {
+			errs = append(errs, err)
+		}
 		n.add(text, cfg, nil)
-		plan.update(n.last())
+		spec := n.last()
+		spec.exclude = !match(spec.labels)
+		plan.update(spec)
+		*nodes = append(*nodes, spec.describe(text))
+		if cfg.focus && !spec.pend && !spec.exclude {
+			focused = append(focused, spec.location.String()+": "+strings.Join(append(path, text), "/"))
+		}
 	})
 	n.level()
 	n.sort()
-	return plan
+	sort.Strings(plan.Labels)
+	return plan, focused, errs
 }
 
 func (p *Plan) update(n *node) {
-	if n.focus && !n.pend {
+	if n.focus && !n.pend && !n.exclude {
 		p.HasFocus = true
 	}
 	if n.order == orderRandom {
 		p.HasRandom = true
 	}
+	p.Labels = mergeLabels(p.Labels, n.labels)
 	if n.nodes == nil {
 		p.Total++
-		if n.focus && !n.pend {
+		switch {
+		case n.exclude:
+			p.Excluded++
+		case n.focus && !n.pend:
 			p.Focused++
-		} else if n.pend {
+		case n.pend:
 			p.Pending++
 		}
 	}
@@ -72,18 +118,41 @@ func (n *node) add(text string, cfg *config, nodes tree) {
 		name = nil
 	}
 	n.nodes = append(n.nodes, node{
-		text:  append(append([]string(nil), name...), text),
-		loc:   append(append([]int(nil), n.loc...), len(n.nodes)),
-		seed:  n.seed,
-		order: cfg.order.or(n.order),
-		scope: cfg.scope.or(n.scope),
-		nest:  cfg.nest.or(n.nest),
-		pend:  cfg.pend || n.pend,
-		focus: cfg.focus || n.focus,
-		nodes: nodes,
+		text:     append(append([]string(nil), name...), text),
+		loc:      append(append([]int(nil), n.loc...), len(n.nodes)),
+		location: cfg.location,
+		seed:     n.seed,
+		order:    cfg.order.or(n.order),
+		scope:    cfg.scope.or(n.scope),
+		nest:     cfg.nest.or(n.nest),
+		pend:     cfg.pend || n.pend,
+		focus:    cfg.focus || n.focus,
+		labels:   mergeLabels(n.labels, cfg.labels),
+		timeout:  defaultZeroDuration(cfg.timeout, n.timeout),
+		nodes:    nodes,
 	})
 }
 
+// describe returns a Node that describes n as it is defined.
+func (n *node) describe(text string) Node {
+	desc := Node{
+		Text:     text,
+		Location: n.location,
+		Order:    n.order.String(),
+		Global:   n.scope == scopeGlobal,
+		Nested:   n.nest == nestOn,
+		Pending:  n.pend,
+		Focused:  n.focus,
+		Excluded: n.exclude,
+		Labels:   n.labels,
+		Index:    append([]int(nil), n.loc...),
+	}
+	if n.nodes != nil {
+		desc.Nodes = []Node{}
+	}
+	return desc
+}
+
 func (n *node) sort() {
 	nodes := n.nodes
 	switch n.order {
@@ -117,6 +186,10 @@ func (n *node) level() {
 	}
 }
 
+func (n node) skip(hasFocus bool) bool {
+	return n.pend || n.exclude || hasFocus && !n.focus
+}
+
 func (n *node) last() *node {
 	return &n.nodes[len(n.nodes)-1]
 }
@@ -130,14 +203,31 @@ func (n node) run(t *testing.T, f func(*testing.T, node)) bool {
 	name := strings.Join(n.text, "/")
 	switch {
 	case n.nodes == nil:
-		return t.Run(name, func(t *testing.T) { f(t, n) })
+		return t.Run(name, func(t *testing.T) {
+			t.Helper()
+			f(t, n)
+		})
 	case n.nested():
-		return t.Run(name, func(t *testing.T) { n.nodes.run(t, f) })
+		return t.Run(name, func(t *testing.T) {
+			t.Helper()
+			n.nodes.run(t, f)
+		})
 	default:
 		return n.nodes.run(t, f)
 	}
 }
 
+// specs calls f for each spec in the tree, in the order they will run.
+func (n node) specs(f func(node)) {
+	if n.nodes == nil {
+		f(n)
+		return
+	}
+	for _, child := range n.nodes {
+		child.specs(f)
+	}
+}
+
 type tree []node
 
 func (ns tree) run(t *testing.T, f func(*testing.T, node)) bool {
@@ -0,0 +1,144 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+)
+
+// GitHub reports specs as GitHub Actions workflow commands, so that failed,
+// focused, and pending specs are annotated at their source locations.
+// If the GITHUB_STEP_SUMMARY environment variable is set, a Markdown summary
+// of the suite is appended to the file it contains after all specs complete.
+// GitHub must be passed to spec.Report as a pointer.
+type GitHub struct {
+	// Writer receives the workflow commands. If nil, os.Stdout is used.
+	Writer io.Writer
+
+	plan spec.Plan
+}
+
+func (gh *GitHub) Start(_ *testing.T, plan spec.Plan) {
+	gh.plan = plan
+	gh.warnings(plan.Nodes, spec.Node{})
+}
+
+// warnings annotates each node that is explicitly focused or pending,
+// ignoring nodes that inherit focus or pend from the parent node.
+func (gh *GitHub) warnings(nodes []spec.Node, parent spec.Node) {
+	for _, n := range nodes {
+		switch {
+		case n.Pending && !parent.Pending:
+			gh.command("warning", n.Location, "Pending", "Pending: "+n.Text)
+		case n.Focused && !parent.Focused && !n.Pending:
+			gh.command("warning", n.Location, "Focused", "Focused: "+n.Text)
+		}
+		gh.warnings(n.Nodes, n)
+	}
+}
+
+func (gh *GitHub) Specs(t *testing.T, specs <-chan spec.Spec) {
+	t.Helper()
+	var passed, failed, skipped int
+	var failures []string
+	for s := range specs {
+		switch {
+		case s.Failed:
+			failed++
+			text := strings.Join(s.Text, "/")
+			msg := "Failed: " + text
+			if p := s.Panic; p != nil {
+				msg += fmt.Sprintf("\nPanic in %s: %v", p.Phase, p.Value)
+			}
+			if out, err := ioutil.ReadAll(s.Out); err == nil && len(out) > 0 {
+				msg += "\n" + strings.TrimRight(string(out), "\n")
+			}
+			gh.command("error", s.Location, "Spec failed", msg)
+			line := fmt.Sprintf("- `%s`", text)
+			if loc := relLocation(s.Location).String(); loc != "" {
+				line += fmt.Sprintf(" (%s)", loc)
+			}
+			failures = append(failures, line)
+		case s.Skipped:
+			skipped++
+		default:
+			passed++
+		}
+	}
+
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return
+	}
+	summary := []string{
+		"### " + gh.plan.Text,
+		"",
+		"| Passed | Failed | Skipped |",
+		"| --- | --- | --- |",
+		fmt.Sprintf("| %d | %d | %d |", passed, failed, skipped),
+	}
+	if len(failures) > 0 {
+		summary = append(summary, "", "**Failed specs:**", "")
+		summary = append(summary, failures...)
+	}
+	if err := appendFile(path, strings.Join(summary, "\n")+"\n\n"); err != nil {
+		t.Error("Failed to write GitHub step summary:", err)
+	}
+}
+
+func (gh *GitHub) command(name string, loc spec.Location, title, msg string) {
+	w := gh.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	var props []string
+	if loc := relLocation(loc); loc.File != "" {
+		props = append(props,
+			"file="+escapeProperty(loc.File),
+			fmt.Sprintf("line=%d", loc.Line),
+		)
+	}
+	props = append(props, "title="+escapeProperty(title))
+	fmt.Fprintf(w, "::%s %s::%s\n", name, strings.Join(props, ","), escapeData(msg))
+}
+
+// relLocation returns the location relative to GITHUB_WORKSPACE, if the
+// location is inside of it.
+func relLocation(loc spec.Location) spec.Location {
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" || loc.File == "" {
+		return loc
+	}
+	rel, err := filepath.Rel(workspace, filepath.FromSlash(loc.File))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return loc
+	}
+	loc.File = filepath.ToSlash(rel)
+	return loc
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+func appendFile(path, text string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,65 @@
+package report_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec/report"
+)
+
+func TestGitHub(t *testing.T) {
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_WORKSPACE", "/src")
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+
+	plan := testPlan()
+	plan.HasFocus = true
+	plan.Nodes[0].Focused = true
+	plan.Nodes[0].Nodes[0].Focused = true
+	plan.Nodes[0].Nodes[1].Focused = true
+	specs := testSpecs()
+	specs[2].Location.File = "/src/app/app,test.go"
+
+	out := &bytes.Buffer{}
+	run(t, &report.GitHub{Writer: out}, plan, specs...)
+
+	compare(t, "workflow commands", out.String(), ""+
+		"::warning file=app/app_test.go,line=10,title=Focused::Focused: G\n"+
+		"::warning file=app/app_test.go,line=15,title=Pending::Pending: S.Pend\n"+
+		"::error file=app/app%2Ctest.go,line=12,title=Spec failed::"+
+		"Failed: G/S.Fail%0APanic in S: some panic%0Asome output%0Amore output\n",
+	)
+
+	md, err := ioutil.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compare(t, "step summary", string(md), "### Suite\n"+
+		"\n"+
+		"| Passed | Failed | Skipped |\n"+
+		"| --- | --- | --- |\n"+
+		"| 1 | 1 | 2 |\n"+
+		"\n"+
+		"**Failed specs:**\n"+
+		"\n"+
+		"- `G/S.Fail` (app/app,test.go:12)\n"+
+		"\n",
+	)
+}
+
+func TestGitHubOutsideWorkspace(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", "/other")
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+
+	out := &bytes.Buffer{}
+	specs := testSpecs()
+	run(t, &report.GitHub{Writer: out}, testPlan(), specs[3], specs[2])
+
+	compare(t, "workflow commands", out.String(), ""+
+		"::warning file=/src/app/app_test.go,line=15,title=Pending::Pending: S.Pend\n"+
+		"::error file=/src/app/app_test.go,line=12,title=Spec failed::"+
+		"Failed: G/S.Fail%0APanic in S: some panic%0Asome output%0Amore output\n",
+	)
+}
@@ -0,0 +1,243 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+)
+
+// JSON reports specs by writing one JSON object per line as the suite runs.
+// The first object describes the suite, each following object describes a
+// completed spec, and the last object contains the totals for the suite.
+// Each object has an "event" field set to "start", "spec", or "end".
+// Durations are in seconds.
+// JSON must be passed to spec.Report as a pointer.
+type JSON struct {
+	// Writer receives the report. If nil, os.Stdout is used.
+	Writer io.Writer
+
+	// Path is the location of a file that receives the report.
+	// If set, Writer is ignored.
+	Path string
+
+	enc   *json.Encoder
+	file  *os.File
+	start time.Time
+	err   error
+}
+
+func (j *JSON) Start(t *testing.T, plan spec.Plan) {
+	t.Helper()
+	j.start = time.Now()
+	w := j.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	if j.Path != "" {
+		j.file, j.err = os.Create(j.Path)
+		if j.err != nil {
+			t.Error("Failed to write JSON report:", j.err)
+			return
+		}
+		w = j.file
+	}
+	j.enc = json.NewEncoder(w)
+	j.write(t, jsonEvent{
+		Event: "start",
+		Time:  j.start,
+		Plan: &jsonPlan{
+			Text:      plan.Text,
+			Total:     plan.Total,
+			Pending:   plan.Pending,
+			Focused:   plan.Focused,
+			Excluded:  plan.Excluded,
+			Seed:      plan.Seed,
+			HasRandom: plan.HasRandom,
+			HasFocus:  plan.HasFocus,
+			Labels:    jsonStrings(plan.Labels),
+			Nodes:     jsonNodes(plan.Nodes),
+		},
+	})
+}
+
+func (j *JSON) Specs(t *testing.T, specs <-chan spec.Spec) {
+	t.Helper()
+	var totals jsonTotals
+	for s := range specs {
+		switch {
+		case s.Failed:
+			totals.Failed++
+		case s.Skipped:
+			totals.Skipped++
+		default:
+			totals.Passed++
+		}
+		j.write(t, jsonEvent{
+			Event: "spec",
+			Time:  time.Now(),
+			Spec:  newJSONSpec(s),
+		})
+	}
+	totals.Duration = time.Since(j.start).Seconds()
+	j.write(t, jsonEvent{
+		Event:  "end",
+		Time:   time.Now(),
+		Totals: &totals,
+	})
+	if j.file != nil && j.err == nil {
+		if err := j.file.Close(); err != nil {
+			t.Error("Failed to write JSON report:", err)
+		}
+	}
+}
+
+func (j *JSON) write(t *testing.T, e jsonEvent) {
+	t.Helper()
+	if j.err != nil {
+		return
+	}
+	if j.err = j.enc.Encode(e); j.err != nil {
+		t.Error("Failed to write JSON report:", j.err)
+	}
+}
+
+type jsonEvent struct {
+	Event  string      `json:"event"`
+	Time   time.Time   `json:"time"`
+	Plan   *jsonPlan   `json:"plan,omitempty"`
+	Spec   *jsonSpec   `json:"spec,omitempty"`
+	Totals *jsonTotals `json:"totals,omitempty"`
+}
+
+type jsonPlan struct {
+	Text      string     `json:"text"`
+	Total     int        `json:"total"`
+	Pending   int        `json:"pending"`
+	Focused   int        `json:"focused"`
+	Excluded  int        `json:"excluded"`
+	Seed      int64      `json:"seed"`
+	HasRandom bool       `json:"has_random"`
+	HasFocus  bool       `json:"has_focus"`
+	Labels    []string   `json:"labels"`
+	Nodes     []jsonNode `json:"nodes"`
+}
+
+type jsonNode struct {
+	Text     string     `json:"text"`
+	File     string     `json:"file,omitempty"`
+	Line     int        `json:"line,omitempty"`
+	Order    string     `json:"order"`
+	Global   bool       `json:"global"`
+	Nested   bool       `json:"nested"`
+	Pending  bool       `json:"pending"`
+	Focused  bool       `json:"focused"`
+	Excluded bool       `json:"excluded"`
+	Labels   []string   `json:"labels"`
+	Index    []int      `json:"index"`
+	Nodes    []jsonNode `json:"nodes,omitempty"`
+}
+
+func jsonNodes(nodes []spec.Node) []jsonNode {
+	out := []jsonNode{}
+	for _, n := range nodes {
+		node := jsonNode{
+			Text:     n.Text,
+			File:     n.Location.File,
+			Line:     n.Location.Line,
+			Order:    n.Order,
+			Global:   n.Global,
+			Nested:   n.Nested,
+			Pending:  n.Pending,
+			Focused:  n.Focused,
+			Excluded: n.Excluded,
+			Labels:   jsonStrings(n.Labels),
+			Index:    n.Index,
+		}
+		if n.Nodes != nil {
+			node.Nodes = jsonNodes(n.Nodes)
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+type jsonSpec struct {
+	Text           []string   `json:"text"`
+	Index          []int      `json:"index"`
+	File           string     `json:"file,omitempty"`
+	Line           int        `json:"line,omitempty"`
+	Failed         bool       `json:"failed"`
+	Skipped        bool       `json:"skipped"`
+	Focused        bool       `json:"focused"`
+	Parallel       bool       `json:"parallel"`
+	Labels         []string   `json:"labels"`
+	Start          *time.Time `json:"start,omitempty"`
+	Duration       float64    `json:"duration"`
+	BeforeDuration float64    `json:"before_duration"`
+	BodyDuration   float64    `json:"body_duration"`
+	AfterDuration  float64    `json:"after_duration"`
+	Panic          *jsonPanic `json:"panic,omitempty"`
+	Output         string     `json:"output"`
+}
+
+type jsonPanic struct {
+	Value string `json:"value"`
+	Stack string `json:"stack"`
+	Phase string `json:"phase"`
+	Level int    `json:"level"`
+}
+
+func newJSONSpec(s spec.Spec) *jsonSpec {
+	js := &jsonSpec{
+		Text:           s.Text,
+		Index:          s.Index,
+		File:           s.Location.File,
+		Line:           s.Location.Line,
+		Failed:         s.Failed,
+		Skipped:        s.Skipped,
+		Focused:        s.Focused,
+		Parallel:       s.Parallel,
+		Labels:         jsonStrings(s.Labels),
+		Duration:       s.Duration.Seconds(),
+		BeforeDuration: s.BeforeDuration.Seconds(),
+		BodyDuration:   s.BodyDuration.Seconds(),
+		AfterDuration:  s.AfterDuration.Seconds(),
+	}
+	if !s.Start.IsZero() {
+		js.Start = &s.Start
+	}
+	if p := s.Panic; p != nil {
+		js.Panic = &jsonPanic{
+			Value: fmt.Sprint(p.Value),
+			Stack: p.Stack,
+			Phase: p.Phase,
+			Level: p.Level,
+		}
+	}
+	if out, err := ioutil.ReadAll(s.Out); err == nil {
+		js.Output = string(out)
+	}
+	return js
+}
+
+type jsonTotals struct {
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Skipped  int     `json:"skipped"`
+	Duration float64 `json:"duration"`
+}
+
+// jsonStrings returns an empty slice instead of nil, so that the field is
+// encoded as [] instead of null.
+func jsonStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
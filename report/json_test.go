@@ -0,0 +1,80 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec/report"
+)
+
+func TestJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	specs := testSpecs()
+	specs[3].Start = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	specs[3].Labels = []string{"some-label"}
+	run(t, &report.JSON{Writer: out}, testPlan(), specs...)
+
+	compare(t, "JSON report", normalizeJSON(t, out.String()), strings.Join([]string{
+		`{"event":"start","plan":{"excluded":0,"focused":0,"has_focus":false,"has_random":true,"labels":[],"nodes":[` +
+			`{"excluded":false,"file":"/src/app/app_test.go","focused":false,"global":false,"index":[0],"labels":[],"line":10,"nested":false,"nodes":[` +
+			`{"excluded":false,"file":"/src/app/app_test.go","focused":false,"global":false,"index":[0,0],"labels":[],"line":11,"nested":false,"order":"","pending":false,"text":"S.Pass"},` +
+			`{"excluded":false,"file":"/src/app/app_test.go","focused":false,"global":false,"index":[0,1],"labels":[],"line":12,"nested":false,"order":"","pending":false,"text":"S.Fail"}` +
+			`],"order":"","pending":false,"text":"G"},` +
+			`{"excluded":true,"file":"/src/app/app_test.go","focused":false,"global":false,"index":[1],"labels":[],"line":14,"nested":false,"order":"","pending":false,"text":"S.Skip"},` +
+			`{"excluded":false,"file":"/src/app/app_test.go","focused":false,"global":false,"index":[2],"labels":[],"line":15,"nested":false,"order":"","pending":true,"text":"S.Pend"}` +
+			`],"pending":1,"seed":2,"text":"Suite","total":4},"time":"TIME"}`,
+		`{"event":"spec","spec":{"after_duration":0,"before_duration":0,"body_duration":0,"duration":0,"failed":false,"file":"/src/app/app_test.go","focused":false,"index":[2],"labels":[],"line":15,"output":"","parallel":false,"skipped":true,"text":["S.Pend"]},"time":"TIME"}`,
+		`{"event":"spec","spec":{"after_duration":0,"before_duration":0,"body_duration":0,"duration":0,"failed":false,"file":"/src/app/app_test.go","focused":false,"index":[1],"labels":[],"line":14,"output":"","parallel":false,"skipped":true,"text":["S.Skip"]},"time":"TIME"}`,
+		`{"event":"spec","spec":{"after_duration":0,"before_duration":0,"body_duration":0,"duration":0.0025,"failed":true,"file":"/src/app/app_test.go","focused":false,"index":[0,1],"labels":[],"line":12,"output":"some output\nmore output\n",` +
+			`"panic":{"level":1,"phase":"S","stack":"app_test.testApp.func1()\n\t/src/app/app_test.go:12 +0x25","value":"some panic"},"parallel":false,"skipped":false,"text":["G","S.Fail"]},"time":"TIME"}`,
+		`{"event":"spec","spec":{"after_duration":0,"before_duration":0,"body_duration":0,"duration":0.0015,"failed":false,"file":"/src/app/app_test.go","focused":false,"index":[0,0],"labels":["some-label"],"line":11,"output":"","parallel":false,"skipped":false,"start":"2020-01-02T03:04:05Z","text":["G","S.Pass"]},"time":"TIME"}`,
+		`{"event":"end","time":"TIME","totals":{"duration":"DURATION","failed":1,"passed":1,"skipped":2}}`,
+	}, "\n"))
+}
+
+func TestJSONPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	run(t, &report.JSON{Path: path}, testPlan(), testSpecs()...)
+
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(out)), "\n"); len(lines) != 6 {
+		t.Fatal("Incorrect number of events:", len(lines))
+	}
+}
+
+// normalizeJSON replaces the times in each event with placeholders and
+// sorts the keys of each object.
+func normalizeJSON(t *testing.T, out string) string {
+	t.Helper()
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatal("Invalid JSON:", line)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, event["time"].(string)); err != nil {
+			t.Fatal("Invalid time:", line)
+		}
+		event["time"] = "TIME"
+		if totals, ok := event["totals"].(map[string]interface{}); ok {
+			if _, ok := totals["duration"].(float64); !ok {
+				t.Fatal("Invalid duration:", line)
+			}
+			totals["duration"] = "DURATION"
+		}
+		normalized, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(normalized))
+	}
+	return strings.Join(lines, "\n")
+}
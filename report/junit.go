@@ -0,0 +1,178 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+)
+
+// JUnit reports specs by writing a JUnit XML file after all specs complete.
+// JUnit must be passed to spec.Report as a pointer.
+type JUnit struct {
+	// Path is the location of the XML file.
+	Path string
+
+	plan spec.Plan
+}
+
+func (j *JUnit) Start(_ *testing.T, plan spec.Plan) {
+	j.plan = plan
+}
+
+func (j *JUnit) Specs(t *testing.T, specs <-chan spec.Spec) {
+	t.Helper()
+	// Pending specs are counted as disabled instead of skipped, so that
+	// consumers do not count them twice.
+	suite := junitSuite{
+		Name:     j.plan.Text,
+		Tests:    j.plan.Total,
+		Disabled: j.plan.Pending,
+	}
+	if j.plan.HasRandom {
+		suite.Properties = append(suite.Properties, junitProperty{
+			Name:  "seed",
+			Value: strconv.FormatInt(j.plan.Seed, 10),
+		})
+	}
+	if j.plan.HasFocus {
+		suite.Properties = append(suite.Properties, junitProperty{
+			Name:  "focus",
+			Value: "true",
+		})
+	}
+	var total time.Duration
+	for s := range specs {
+		c := junitCase{
+			Name:      strings.Join(s.Text, "/"),
+			Classname: j.plan.Text,
+			File:      s.Location.File,
+			Line:      s.Location.Line,
+			Time:      seconds(s.Duration),
+		}
+		total += s.Duration
+		switch {
+		case s.Failed:
+			suite.Failures++
+			c.Failure = &junitResult{Message: "Failed"}
+			if p := s.Panic; p != nil {
+				c.Failure.Message = fmt.Sprintf("Panic in %s: %v", p.Phase, p.Value)
+				c.Failure.Text = p.Stack
+			}
+		case s.Skipped && pending(j.plan, s):
+			c.Skipped = &junitResult{Message: "Pending"}
+		case s.Skipped:
+			suite.Skipped++
+			c.Skipped = &junitResult{Message: "Skipped"}
+		}
+		if out, err := ioutil.ReadAll(s.Out); err == nil {
+			c.SystemOut = string(out)
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Time = seconds(total)
+	if err := j.write(junitSuites{
+		Name:     j.plan.Text,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Disabled: suite.Disabled,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}); err != nil {
+		t.Error("Failed to write JUnit report:", err)
+	}
+}
+
+func (j *JUnit) write(suites junitSuites) error {
+	f, err := os.Create(j.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	if _, err := f.WriteString("\n"); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Disabled int          `xml:"disabled,attr"`
+	Time     float64      `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Disabled   int             `xml:"disabled,attr"`
+	Time       float64         `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string       `xml:"name,attr"`
+	Classname string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr,omitempty"`
+	Line      int          `xml:"line,attr,omitempty"`
+	Time      float64      `xml:"time,attr"`
+	Failure   *junitResult `xml:"failure"`
+	Skipped   *junitResult `xml:"skipped"`
+	SystemOut string       `xml:"system-out,omitempty"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// pending returns true if the spec is counted as pending by the plan.
+func pending(plan spec.Plan, s spec.Spec) bool {
+	n, ok := planNode(plan.Nodes, s.Index)
+	return ok && n.Pending && !n.Excluded
+}
+
+// planNode returns the node at the provided index in the tree.
+func planNode(nodes []spec.Node, index []int) (spec.Node, bool) {
+	var n spec.Node
+	for _, i := range index {
+		if i < 0 || i >= len(nodes) {
+			return spec.Node{}, false
+		}
+		n = nodes[i]
+		nodes = n.Nodes
+	}
+	return n, len(index) > 0
+}
+
+func seconds(d time.Duration) float64 {
+	return math.Round(d.Seconds()*1000) / 1000
+}
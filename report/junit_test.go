@@ -0,0 +1,60 @@
+package report_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestJUnit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	run(t, &report.JUnit{Path: path}, testPlan(), testSpecs()...)
+
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compare(t, "JUnit report", string(out), `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="Suite" tests="4" failures="1" skipped="1" disabled="1" time="0.004">
+  <testsuite name="Suite" tests="4" failures="1" skipped="1" disabled="1" time="0.004">
+    <properties>
+      <property name="seed" value="2"></property>
+    </properties>
+    <testcase name="S.Pend" classname="Suite" file="/src/app/app_test.go" line="15" time="0">
+      <skipped message="Pending"></skipped>
+    </testcase>
+    <testcase name="S.Skip" classname="Suite" file="/src/app/app_test.go" line="14" time="0">
+      <skipped message="Skipped"></skipped>
+    </testcase>
+    <testcase name="G/S.Fail" classname="Suite" file="/src/app/app_test.go" line="12" time="0.003">
+      <failure message="Panic in S: some panic">app_test.testApp.func1()&#xA;&#x9;/src/app/app_test.go:12 +0x25</failure>
+      <system-out>some output&#xA;more output&#xA;</system-out>
+    </testcase>
+    <testcase name="G/S.Pass" classname="Suite" file="/src/app/app_test.go" line="11" time="0.002"></testcase>
+  </testsuite>
+</testsuites>
+`)
+}
+
+func TestJUnitTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	run(t, &report.JUnit{Path: path}, testPlan(),
+		spec.Spec{Text: []string{"G", "S.Pass"}, Index: []int{0, 0}, Duration: 100 * time.Millisecond},
+		spec.Spec{Text: []string{"G", "S.Fail"}, Index: []int{0, 1}, Duration: 200 * time.Millisecond},
+		spec.Spec{Text: []string{"S.Fast.1"}, Index: []int{1}, Duration: 400 * time.Microsecond},
+		spec.Spec{Text: []string{"S.Fast.2"}, Index: []int{2}, Duration: 400 * time.Microsecond},
+	)
+
+	out, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(out), ` time="0.301"`); n != 2 {
+		t.Fatalf("Incorrect suite time:\n%s", out)
+	}
+}
@@ -3,12 +3,20 @@ package report
 import (
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/sclevine/spec"
 )
 
 // Log reports specs via the testing log methods and only affects verbose runs.
-type Log struct{}
+type Log struct {
+	// Slowest is the number of slowest specs listed after the suite runs.
+	Slowest int
+
+	// Threshold lists every spec that takes longer than the provided
+	// duration after the suite runs. It is ignored if zero.
+	Threshold time.Duration
+}
 
 func (Log) Start(t *testing.T, plan spec.Plan) {
 	t.Helper()
@@ -22,10 +30,12 @@ func (Log) Start(t *testing.T, plan spec.Plan) {
 	}
 }
 
-func (Log) Specs(t *testing.T, specs <-chan spec.Spec) {
+func (l Log) Specs(t *testing.T, specs <-chan spec.Spec) {
 	t.Helper()
 	var passed, failed, skipped int
+	var times timings
 	for s := range specs {
+		times.add(s)
 		switch {
 		case s.Failed:
 			failed++
@@ -41,4 +51,7 @@ func (Log) Specs(t *testing.T, specs <-chan spec.Spec) {
 		}
 	}
 	t.Logf("Passed: %d | Failed: %d | Skipped: %d", passed, failed, skipped)
+	for _, line := range times.summary(l.Slowest, l.Threshold) {
+		t.Log(line)
+	}
 }
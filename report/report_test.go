@@ -0,0 +1,73 @@
+package report_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+)
+
+// run sends the provided plan and specs to the reporter, as Run would.
+func run(t *testing.T, r spec.Reporter, plan spec.Plan, specs ...spec.Spec) {
+	t.Helper()
+	r.Start(t, plan)
+	ch := make(chan spec.Spec, len(specs))
+	for _, s := range specs {
+		if s.Out == nil {
+			s.Out = strings.NewReader("")
+		}
+		ch <- s
+	}
+	close(ch)
+	r.Specs(t, ch)
+}
+
+func testPlan() spec.Plan {
+	return spec.Plan{
+		Text:      "Suite",
+		Total:     4,
+		Pending:   1,
+		Seed:      2,
+		HasRandom: true,
+		Nodes: []spec.Node{
+			{Text: "G", Index: []int{0}, Location: spec.Location{File: "/src/app/app_test.go", Line: 10}, Nodes: []spec.Node{
+				{Text: "S.Pass", Index: []int{0, 0}, Location: spec.Location{File: "/src/app/app_test.go", Line: 11}},
+				{Text: "S.Fail", Index: []int{0, 1}, Location: spec.Location{File: "/src/app/app_test.go", Line: 12}},
+			}},
+			{Text: "S.Skip", Index: []int{1}, Location: spec.Location{File: "/src/app/app_test.go", Line: 14}, Excluded: true},
+			{Text: "S.Pend", Index: []int{2}, Location: spec.Location{File: "/src/app/app_test.go", Line: 15}, Pending: true},
+		},
+	}
+}
+
+// testSpecs returns specs for testPlan, in the reverse of the order they
+// are defined.
+func testSpecs() []spec.Spec {
+	return []spec.Spec{
+		{Text: []string{"S.Pend"}, Index: []int{2}, Skipped: true, Location: spec.Location{File: "/src/app/app_test.go", Line: 15}},
+		{Text: []string{"S.Skip"}, Index: []int{1}, Skipped: true, Location: spec.Location{File: "/src/app/app_test.go", Line: 14}},
+		{
+			Text: []string{"G", "S.Fail"}, Index: []int{0, 1}, Failed: true,
+			Location: spec.Location{File: "/src/app/app_test.go", Line: 12},
+			Duration: 2500 * time.Microsecond,
+			Out:      strings.NewReader("some output\nmore output\n"),
+			Panic: &spec.Panic{
+				Value: "some panic", Phase: "S", Level: 1,
+				Stack: "app_test.testApp.func1()\n\t/src/app/app_test.go:12 +0x25",
+			},
+		},
+		{
+			Text: []string{"G", "S.Pass"}, Index: []int{0, 0},
+			Location: spec.Location{File: "/src/app/app_test.go", Line: 11},
+			Duration: 1500 * time.Microsecond,
+		},
+	}
+}
+
+func compare(t *testing.T, name, actual, expected string) {
+	t.Helper()
+	if actual != expected {
+		t.Fatalf("Incorrect %s:\n--- actual ---\n%s\n--- expected ---\n%s", name, actual, expected)
+	}
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sclevine/spec"
+)
+
+type timings []spec.Spec
+
+func (ts *timings) add(s spec.Spec) {
+	if s.Start.IsZero() {
+		return
+	}
+	s.Out = nil
+	*ts = append(*ts, s)
+}
+
+func (ts timings) summary(slowest int, threshold time.Duration) []string {
+	sorted := append(timings(nil), ts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+	var lines []string
+	if slowest > 0 && len(sorted) > 0 {
+		if slowest > len(sorted) {
+			slowest = len(sorted)
+		}
+		lines = append(lines, fmt.Sprintf("Slowest %d specs:", slowest))
+		for _, s := range sorted[:slowest] {
+			lines = append(lines, timingLine(s))
+		}
+	}
+	if threshold > 0 {
+		var slow []string
+		for _, s := range sorted {
+			if s.Duration > threshold {
+				slow = append(slow, timingLine(s))
+			}
+		}
+		if len(slow) > 0 {
+			lines = append(lines, fmt.Sprintf("Specs slower than %s: %d", threshold, len(slow)))
+			lines = append(lines, slow...)
+		}
+	}
+	return lines
+}
+
+func timingLine(s spec.Spec) string {
+	return fmt.Sprintf("%12s  %s", s.Duration.Round(time.Microsecond), strings.Join(s.Text, "/"))
+}
@@ -0,0 +1,72 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec/report"
+)
+
+func TestTerminalSlowest(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		slowest   int
+		threshold time.Duration
+		expected  string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "slowest", slowest: 1,
+			expected: "Slowest 1 specs:\n" +
+				"       2.5ms  G/S.Fail\n",
+		},
+		{
+			name: "slowest more than total", slowest: 10,
+			expected: "Slowest 2 specs:\n" +
+				"       2.5ms  G/S.Fail\n" +
+				"       1.5ms  G/S.Pass\n",
+		},
+		{
+			name: "threshold", threshold: 2 * time.Millisecond,
+			expected: "Specs slower than 2ms: 1\n" +
+				"       2.5ms  G/S.Fail\n",
+		},
+		{
+			name: "threshold above all specs", threshold: time.Second,
+		},
+		{
+			name: "slowest and threshold", slowest: 2, threshold: time.Millisecond,
+			expected: "Slowest 2 specs:\n" +
+				"       2.5ms  G/S.Fail\n" +
+				"       1.5ms  G/S.Pass\n" +
+				"Specs slower than 1ms: 2\n" +
+				"       2.5ms  G/S.Fail\n" +
+				"       1.5ms  G/S.Pass\n",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			specs := testSpecs()
+			specs[2].Start = time.Now()
+			specs[3].Start = time.Now()
+			out := &bytes.Buffer{}
+			run(t, report.Terminal{
+				Writer:    out,
+				Color:     report.Off,
+				Verbose:   report.Off,
+				Slowest:   c.slowest,
+				Threshold: c.threshold,
+			}, testPlan(), specs...)
+
+			compare(t, "terminal report", out.String(), "Suite: Suite\n"+
+				"Total: 4 | Focused: 0 | Pending: 1\n"+
+				"Random seed: 2\n"+
+				"ssx.\n"+
+				"Passed: 1 | Failed: 1 | Skipped: 2\n"+
+				c.expected+
+				"\n")
+		})
+	}
+}
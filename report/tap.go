@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+)
+
+// TAP reports specs in Test Anything Protocol (TAP) version 14 format.
+// Failed specs and specs with output include a YAML diagnostic block.
+type TAP struct {
+	// Writer receives the report. If nil, os.Stdout is used.
+	Writer io.Writer
+}
+
+func (tap TAP) Start(_ *testing.T, plan spec.Plan) {
+	w := tap.writer()
+	fmt.Fprintln(w, "TAP version 14")
+	fmt.Fprintf(w, "1..%d\n", plan.Total)
+	fmt.Fprintln(w, "# Suite:", plan.Text)
+	if plan.HasRandom {
+		fmt.Fprintln(w, "# Random seed:", plan.Seed)
+	}
+	if plan.HasFocus {
+		fmt.Fprintln(w, "# Focus is active.")
+	}
+}
+
+func (tap TAP) Specs(_ *testing.T, specs <-chan spec.Spec) {
+	w := tap.writer()
+	n := 0
+	for s := range specs {
+		n++
+		status := "ok"
+		if s.Failed {
+			status = "not ok"
+		}
+		directive := ""
+		if s.Skipped && !s.Failed {
+			directive = " # SKIP"
+		}
+		fmt.Fprintf(w, "%s %d - %s%s\n", status, n, tapEscape(strings.Join(s.Text, "/")), directive)
+
+		var diag []string
+		if s.Failed {
+			if loc := s.Location.String(); loc != "" {
+				diag = append(diag, "at: "+strconv.Quote(loc))
+			}
+			if p := s.Panic; p != nil {
+				diag = append(diag, "message: "+strconv.Quote(fmt.Sprintf("Panic in %s: %v", p.Phase, p.Value)))
+				diag = append(diag, tapBlock("stack", p.Stack)...)
+			}
+		}
+		if out, err := ioutil.ReadAll(s.Out); err == nil && len(out) > 0 {
+			diag = append(diag, tapBlock("output", string(out))...)
+		}
+		if len(diag) > 0 {
+			fmt.Fprintln(w, "  ---")
+			for _, line := range diag {
+				fmt.Fprintln(w, "  "+line)
+			}
+			fmt.Fprintln(w, "  ...")
+		}
+	}
+}
+
+func (tap TAP) writer() io.Writer {
+	if tap.Writer == nil {
+		return os.Stdout
+	}
+	return tap.Writer
+}
+
+// tapEscape escapes characters in a test point description that TAP would
+// otherwise interpret as the start of a directive.
+func tapEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "#", `\#`, "\n", " ").Replace(s)
+}
+
+// tapBlock formats text as a YAML literal block scalar.
+func tapBlock(key, text string) []string {
+	lines := []string{key + ": |-"}
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
@@ -0,0 +1,35 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec/report"
+)
+
+func TestTAP(t *testing.T) {
+	out := &bytes.Buffer{}
+	specs := testSpecs()
+	specs[3].Text = []string{"G", "S.Pass #1"}
+	run(t, report.TAP{Writer: out}, testPlan(), specs...)
+
+	compare(t, "TAP report", out.String(), `TAP version 14
+1..4
+# Suite: Suite
+# Random seed: 2
+ok 1 - S.Pend # SKIP
+ok 2 - S.Skip # SKIP
+not ok 3 - G/S.Fail
+  ---
+  at: "/src/app/app_test.go:12"
+  message: "Panic in S: some panic"
+  stack: |-
+    app_test.testApp.func1()
+    	/src/app/app_test.go:12 +0x25
+  output: |-
+    some output
+    more output
+  ...
+ok 4 - G/S.Pass \#1
+`)
+}
@@ -2,50 +2,151 @@ package report
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sclevine/spec"
 )
 
-// Terminal reports specs via stdout.
-type Terminal struct{}
+// Terminal reports specs via stdout or the provided Writer.
+type Terminal struct {
+	// Writer receives the report. If nil, os.Stdout is used.
+	Writer io.Writer
 
-func (Terminal) Start(_ *testing.T, plan spec.Plan) {
-	fmt.Println("Suite:", plan.Text)
-	fmt.Printf("Total: %d | Focused: %d | Pending: %d\n", plan.Total, plan.Focused, plan.Pending)
+	// Color controls whether the report uses ANSI colors.
+	// If Auto, colors are used when Writer is a terminal and the NO_COLOR
+	// environment variable is not set.
+	Color Setting
+
+	// Verbose controls whether the output of failed specs is displayed
+	// instead of a marker for each spec.
+	// If Auto, output is displayed when go test is run with -v.
+	Verbose Setting
+
+	// Slowest is the number of slowest specs listed after the suite runs.
+	Slowest int
+
+	// Threshold lists every spec that takes longer than the provided
+	// duration after the suite runs. It is ignored if zero.
+	Threshold time.Duration
+}
+
+// A Setting enables or disables a feature of a reporter.
+type Setting int
+
+const (
+	// Auto enables or disables a feature based on the environment.
+	Auto Setting = iota
+
+	// On always enables a feature.
+	On
+
+	// Off always disables a feature.
+	Off
+)
+
+func (s Setting) enabled(auto func() bool) bool {
+	switch s {
+	case On:
+		return true
+	case Off:
+		return false
+	}
+	return auto()
+}
+
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+)
+
+func (term Terminal) Start(_ *testing.T, plan spec.Plan) {
+	w, color := term.writer(), term.colors()
+	fmt.Fprintln(w, "Suite:", plan.Text)
+	fmt.Fprintf(w, "Total: %d | Focused: %d | Pending: %d\n", plan.Total, plan.Focused, plan.Pending)
 	if plan.HasRandom {
-		fmt.Println("Random seed:", plan.Seed)
+		fmt.Fprintln(w, "Random seed:", plan.Seed)
 	}
 	if plan.HasFocus {
-		fmt.Println("Focus is active.")
+		fmt.Fprintln(w, color(colorYellow, "Focus is active."))
 	}
 }
 
-func (Terminal) Specs(_ *testing.T, specs <-chan spec.Spec) {
+func (term Terminal) Specs(_ *testing.T, specs <-chan spec.Spec) {
+	w, color := term.writer(), term.colors()
+	verbose := term.Verbose.enabled(testing.Verbose)
 	var passed, failed, skipped int
+	var times timings
 	for s := range specs {
+		times.add(s)
 		switch {
 		case s.Failed:
 			failed++
-			if !testing.Verbose() {
-				fmt.Print("x")
+			if !verbose {
+				fmt.Fprint(w, color(colorRed, "x"))
 			} else {
+				fmt.Fprintln(w, color(colorRed, "Failed: "+strings.Join(s.Text, "/")))
 				if out, err := ioutil.ReadAll(s.Out); err == nil {
-					fmt.Printf("%s\n", out)
+					fmt.Fprintf(w, "%s\n", out)
 				}
 			}
 		case s.Skipped:
 			skipped++
-			if !testing.Verbose() {
-				fmt.Print("s")
+			if !verbose {
+				fmt.Fprint(w, color(colorYellow, "s"))
 			}
 		default:
 			passed++
-			if !testing.Verbose() {
-				fmt.Print(".")
+			if !verbose {
+				fmt.Fprint(w, color(colorGreen, "."))
 			}
 		}
 	}
-	fmt.Printf("\nPassed: %d | Failed: %d | Skipped: %d\n\n", passed, failed, skipped)
+	summary := fmt.Sprintf("Passed: %d | Failed: %d | Skipped: %d", passed, failed, skipped)
+	if failed > 0 {
+		summary = color(colorRed, summary)
+	} else {
+		summary = color(colorGreen, summary)
+	}
+	fmt.Fprintf(w, "\n%s\n", summary)
+	for _, line := range times.summary(term.Slowest, term.Threshold) {
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+}
+
+func (term Terminal) writer() io.Writer {
+	if term.Writer == nil {
+		return os.Stdout
+	}
+	return term.Writer
+}
+
+// colors returns a function that colors text with the provided ANSI color
+// code, if colors are enabled.
+func (term Terminal) colors() func(code, text string) string {
+	if !term.Color.enabled(term.isTTY) {
+		return func(_, text string) string { return text }
+	}
+	return func(code, text string) string {
+		return "\x1b[" + code + "m" + text + "\x1b[0m"
+	}
+}
+
+// isTTY returns true if the writer is a terminal that supports color.
+func (term Terminal) isTTY() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := term.writer().(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,57 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec/report"
+)
+
+func TestTerminal(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		color    report.Setting
+		verbose  report.Setting
+		noColor  string
+		expected string
+	}{
+		{
+			name: "color off", color: report.Off, verbose: report.Off,
+			expected: "ssx.\nPassed: 1 | Failed: 1 | Skipped: 2\n\n",
+		},
+		{
+			name: "color on", color: report.On, verbose: report.Off,
+			expected: "\x1b[33ms\x1b[0m\x1b[33ms\x1b[0m\x1b[31mx\x1b[0m\x1b[32m.\x1b[0m\n" +
+				"\x1b[31mPassed: 1 | Failed: 1 | Skipped: 2\x1b[0m\n\n",
+		},
+		{
+			name: "color on with NO_COLOR", color: report.On, verbose: report.Off, noColor: "1",
+			expected: "\x1b[33ms\x1b[0m\x1b[33ms\x1b[0m\x1b[31mx\x1b[0m\x1b[32m.\x1b[0m\n" +
+				"\x1b[31mPassed: 1 | Failed: 1 | Skipped: 2\x1b[0m\n\n",
+		},
+		{
+			name: "color auto with NO_COLOR", color: report.Auto, verbose: report.Off, noColor: "1",
+			expected: "ssx.\nPassed: 1 | Failed: 1 | Skipped: 2\n\n",
+		},
+		{
+			name: "color auto without terminal", color: report.Auto, verbose: report.Off,
+			expected: "ssx.\nPassed: 1 | Failed: 1 | Skipped: 2\n\n",
+		},
+		{
+			name: "verbose on", color: report.Off, verbose: report.On,
+			expected: "Failed: G/S.Fail\nsome output\nmore output\n\n" +
+				"\nPassed: 1 | Failed: 1 | Skipped: 2\n\n",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", c.noColor)
+			out := &bytes.Buffer{}
+			run(t, report.Terminal{Writer: out, Color: c.color, Verbose: c.verbose}, testPlan(), testSpecs()...)
+
+			compare(t, "terminal report", out.String(), "Suite: Suite\n"+
+				"Total: 4 | Focused: 0 | Pending: 1\n"+
+				"Random seed: 2\n"+
+				c.expected)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+)
+
+// Tree reports specs as an indented tree of groups and specs after all specs
+// complete. Specs are listed in the order they are defined, regardless of the
+// order they run in. Each spec is marked as passed, failed, pending, skipped,
+// or not run if it was never reported. Output from failed specs is listed
+// after the tree.
+// Tree must be passed to spec.Report as a pointer.
+type Tree struct {
+	// Writer receives the report. If nil, os.Stdout is used.
+	Writer io.Writer
+
+	plan spec.Plan
+}
+
+func (tr *Tree) Start(_ *testing.T, plan spec.Plan) {
+	tr.plan = plan
+}
+
+func (tr *Tree) Specs(_ *testing.T, specs <-chan spec.Spec) {
+	results := map[string]spec.Spec{}
+	for s := range specs {
+		results[fmt.Sprint(s.Index)] = s
+	}
+	w := tr.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	r := treeRender{w: w, results: results}
+	fmt.Fprintln(w, tr.plan.Text)
+	r.nodes(tr.plan.Nodes, 1)
+	if tr.plan.HasRandom {
+		fmt.Fprintln(w, "\nRandom seed:", tr.plan.Seed)
+	}
+	if len(r.failures) > 0 {
+		fmt.Fprintln(w, "\nFailures:")
+		for i, s := range r.failures {
+			fmt.Fprintf(w, "\n  %d) %s\n", i+1, strings.Join(s.Text, "/"))
+			if loc := s.Location.String(); loc != "" {
+				fmt.Fprintf(w, "     %s\n", loc)
+			}
+			if p := s.Panic; p != nil {
+				fmt.Fprintf(w, "     Panic in %s: %v\n", p.Phase, p.Value)
+			}
+			if out, err := ioutil.ReadAll(s.Out); err == nil && len(out) > 0 {
+				fmt.Fprintln(w, treeIndent(strings.TrimRight(string(out), "\n"), "     "))
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nPassed: %d | Failed: %d | Skipped: %d\n\n", r.passed, len(r.failures), r.skipped)
+}
+
+type treeRender struct {
+	w               io.Writer
+	results         map[string]spec.Spec
+	failures        []spec.Spec
+	passed, skipped int
+}
+
+func (r *treeRender) nodes(nodes []spec.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, n := range nodes {
+		if n.Nodes != nil {
+			fmt.Fprintf(r.w, "%s%s\n", indent, n.Text)
+			r.nodes(n.Nodes, depth+1)
+			continue
+		}
+		var marker string
+		s, ok := r.results[fmt.Sprint(n.Index)]
+		switch {
+		case !ok:
+			marker = " (NOT RUN)"
+		case s.Failed:
+			r.failures = append(r.failures, s)
+			marker = fmt.Sprintf(" (FAILED - %d)", len(r.failures))
+		case s.Skipped && n.Pending:
+			r.skipped++
+			marker = " (PENDING)"
+		case s.Skipped:
+			r.skipped++
+			marker = " (SKIPPED)"
+		default:
+			r.passed++
+			marker = " (PASSED)"
+		}
+		fmt.Fprintf(r.w, "%s%s%s\n", indent, n.Text, marker)
+	}
+}
+
+func treeIndent(text, indent string) string {
+	return indent + strings.Replace(text, "\n", "\n"+indent, -1)
+}
@@ -0,0 +1,53 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec/report"
+)
+
+func TestTree(t *testing.T) {
+	out := &bytes.Buffer{}
+	run(t, &report.Tree{Writer: out}, testPlan(), testSpecs()...)
+
+	compare(t, "tree report", out.String(), `Suite
+  G
+    S.Pass (PASSED)
+    S.Fail (FAILED - 1)
+  S.Skip (SKIPPED)
+  S.Pend (PENDING)
+
+Random seed: 2
+
+Failures:
+
+  1) G/S.Fail
+     /src/app/app_test.go:12
+     Panic in S: some panic
+     some output
+     more output
+
+Passed: 1 | Failed: 1 | Skipped: 2
+
+`)
+}
+
+func TestTreeNotRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	specs := testSpecs()
+	run(t, &report.Tree{Writer: out}, testPlan(), specs[1], specs[0], specs[3])
+
+	compare(t, "tree report", out.String(), `Suite
+  G
+    S.Pass (PASSED)
+    S.Fail (NOT RUN)
+  S.Skip (SKIPPED)
+  S.Pend (PENDING)
+
+Random seed: 2
+
+Passed: 1 | Failed: 0 | Skipped: 2
+
+`)
+}
@@ -0,0 +1,61 @@
+package spec
+
+import "testing"
+
+// A Behavior is a reusable group of specs that verifies a subject of type T.
+// Behaviors are defined with Shared and used with Behavior.Behaves.
+type Behavior[T any] struct {
+	text string
+	f    func(*testing.T, G, S, func() T)
+}
+
+// Shared defines a reusable group of specs that verifies any subject of type
+// T, such as each implementation of an interface.
+// The subject function provides the subject being verified, and is typically
+// called in a Before hook or a spec.
+//
+// Example:
+//
+//	var readWriter = spec.Shared("a ReadWriter", func(t *testing.T, when spec.G, it spec.S, subject func() io.ReadWriter) {
+//		it("reads what was written", func() {...})
+//	})
+//
+//	func testFile(t *testing.T, when spec.G, it spec.S) {
+//		readWriter.Behaves(t, when, it, func() io.ReadWriter { return newFile() })
+//	}
+func Shared[T any](text string, f func(t *testing.T, when G, it S, subject func() T)) Behavior[T] {
+	return Behavior[T]{text: text, f: f}
+}
+
+// Behaves defines a group of specs named "behaves like <text>" that verifies
+// the provided subject using the Behavior.
+//
+// Valid Options:
+// Sequential, Random, Reverse, Parallel
+// Local, Global, Flat, Nested
+// Labels, Timeout
+func (b Behavior[T]) Behaves(t *testing.T, when G, it S, subject func() T, opts ...Option) {
+	when(b.name(), func() { b.f(t, when, it, subject) }, opts...)
+}
+
+// Focus focuses the group of specs defined by the Behavior.
+// This skips all specs in the suite except the group and other focused specs.
+//
+// Valid Options:
+// Sequential, Random, Reverse, Parallel
+// Local, Global, Flat, Nested
+// Labels, Timeout
+func (b Behavior[T]) Focus(t *testing.T, when G, it S, subject func() T, opts ...Option) {
+	when.Focus(b.name(), func() { b.f(t, when, it, subject) }, opts...)
+}
+
+// Pend skips the group of specs defined by the Behavior.
+//
+// All Options are ignored.
+func (b Behavior[T]) Pend(t *testing.T, when G, it S, subject func() T, _ ...Option) {
+	when.Pend(b.name(), func() { b.f(t, when, it, subject) })
+}
+
+func (b Behavior[T]) name() string {
+	return "behaves like " + b.text
+}
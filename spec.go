@@ -2,7 +2,12 @@ package spec
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -13,6 +18,7 @@ import (
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
+// Labels, Timeout
 type G func(text string, f func(), opts ...Option)
 
 // Pend skips all specs in the provided group.
@@ -28,13 +34,14 @@ func (g G) Pend(text string, f func(), _ ...Option) {
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
+// Labels, Timeout
 func (g G) Focus(text string, f func(), opts ...Option) {
 	g(text, f, append(opts, func(c *config) { c.focus = true })...)
 }
 
 // S defines a spec.
 //
-// Valid Options: Parallel
+// Valid Options: Parallel, Labels, Timeout
 type S func(text string, f func(), opts ...Option)
 
 // Before runs a function before each spec in the group.
@@ -47,6 +54,57 @@ func (s S) After(f func()) {
 	s("", f, func(c *config) { c.after = true })
 }
 
+// Defer registers a function to run after the current spec and all of its
+// After hooks complete, even if the spec fails or panics.
+// Deferred functions run in the reverse order that they are registered.
+// This allows Before hooks to clean up only the resources they create.
+//
+// Valid context: inside Before hooks and S blocks
+func (s S) Defer(f func()) {
+	s("", f, func(c *config) { c.deferred = true })
+}
+
+// JustBefore runs a function before each spec in the group, after all Before
+// hooks in the group and all of its parent and child groups have run.
+// This allows inner groups to configure values in Before hooks that are used
+// by outer groups immediately before each spec runs.
+// JustBefore hooks in outer groups run before JustBefore hooks in inner groups.
+func (s S) JustBefore(f func()) {
+	s("", f, func(c *config) { c.justBefore = true })
+}
+
+// Around runs a function around each spec in the group.
+// The function must call the provided run function exactly once to run the
+// spec, along with all Before and After hooks in the group and its subgroups.
+// The spec fails if run is not called or is called more than once.
+// Around hooks in outer groups wrap Around hooks in inner groups, and Around
+// hooks in the same group wrap each other in the order they are defined.
+// The run function returns even if the spec calls t.FailNow or t.SkipNow, so
+// that any code after it always runs.
+func (s S) Around(f func(run func())) {
+	s("", nil, func(c *config) { c.around = f })
+}
+
+// BeforeAll runs a function once before the first spec in the group runs.
+// Since groups are re-evaluated for each spec, any values set by the function
+// must be stored in variables declared outside of the suite, such as in the
+// enclosing test function.
+// When specs from multiple groups are interleaved (e.g., by Random and
+// Global), the function runs before the first spec of the group, wherever
+// that spec occurs.
+// If the function fails the spec, all remaining specs in the group fail.
+func (s S) BeforeAll(f func()) {
+	s("", f, func(c *config) { c.beforeAll = true })
+}
+
+// AfterAll runs a function once after the last spec in the group completes,
+// including when specs run in parallel or fail.
+// It only runs if the BeforeAll hooks for the group ran, such that it does not
+// run if a BeforeAll hook in a parent group fails first.
+func (s S) AfterAll(f func()) {
+	s("", f, func(c *config) { c.afterAll = true })
+}
+
 // Pend skips the provided spec.
 //
 // All Options are ignored.
@@ -57,7 +115,7 @@ func (s S) Pend(text string, f func(), _ ...Option) {
 // Focus focuses the provided spec.
 // This skips all specs in the suite except the spec and other focused specs.
 //
-// Valid Options: Parallel
+// Valid Options: Parallel, Labels, Timeout
 func (s S) Focus(text string, f func(), opts ...Option) {
 	s(text, f, append(opts, func(c *config) { c.focus = true })...)
 }
@@ -76,6 +134,34 @@ func (s S) Out() io.Writer {
 	return out
 }
 
+// Context provides a context that is cancelled when the spec completes or
+// when the spec exceeds its Timeout.
+// The text of the spec may be retrieved from the context with TextFromContext.
+//
+// Valid context: inside S blocks only, nil elsewhere
+func (s S) Context() context.Context {
+	var ctx context.Context
+	s("", nil, func(c *config) {
+		c.ctx = func(cx context.Context) {
+			ctx = cx
+		}
+	})
+	return ctx
+}
+
+// TextFromContext returns the text of the spec that a context provided by
+// S.Context belongs to, or nil if the context was not provided by S.Context.
+func TextFromContext(ctx context.Context) []string {
+	text, _ := ctx.Value(textKey{}).([]string)
+	return text
+}
+
+type textKey struct{}
+
+func newContext(text []string) (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.WithValue(context.Background(), textKey{}, text))
+}
+
 // Suite defines a top-level group of specs within a suite.
 // Suite behaves like a top-level version of G.
 // Unlike other testing libraries, it is re-evaluated for each subspec.
@@ -83,6 +169,7 @@ func (s S) Out() io.Writer {
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
+// Labels, Timeout
 type Suite func(text string, f func(*testing.T, G, S), opts ...Option) bool
 
 // Before runs a function before each spec in the suite.
@@ -101,6 +188,24 @@ func (s Suite) After(f func(*testing.T)) bool {
 	}, func(c *config) { c.after = true })
 }
 
+// BeforeAll runs a function once before the first spec in the suite runs.
+// See S.BeforeAll.
+func (s Suite) BeforeAll(f func(*testing.T)) bool {
+	return s("", func(t *testing.T, _ G, _ S) {
+		t.Helper()
+		f(t)
+	}, func(c *config) { c.beforeAll = true })
+}
+
+// AfterAll runs a function once after the last spec in the suite completes.
+// See S.AfterAll.
+func (s Suite) AfterAll(f func(*testing.T)) bool {
+	return s("", func(t *testing.T, _ G, _ S) {
+		t.Helper()
+		f(t)
+	}, func(c *config) { c.afterAll = true })
+}
+
 // Pend skips the provided top-level group of specs.
 //
 // All Options are ignored.
@@ -114,6 +219,7 @@ func (s Suite) Pend(text string, f func(*testing.T, G, S), _ ...Option) bool {
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
+// Labels, Timeout
 func (s Suite) Focus(text string, f func(*testing.T, G, S), opts ...Option) bool {
 	return s(text, f, append(opts, func(c *config) { c.focus = true })...)
 }
@@ -131,15 +237,20 @@ func (s Suite) Run(t *testing.T) bool {
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
-// Seed, Report
+// Seed, Report, FailOnFocus
+// Labels, Filter, Timeout
 func New(text string, opts ...Option) Suite {
 	var fs []func(*testing.T, G, S)
 	return func(newText string, f func(*testing.T, G, S), newOpts ...Option) bool {
 		cfg := options(newOpts).apply()
 		if cfg.t == nil {
+			location := caller()
+			newOpts = append(newOpts[:len(newOpts):len(newOpts)], func(c *config) {
+				c.location = location
+			})
 			fs = append(fs, func(t *testing.T, g G, s S) {
 				var do func(string, func(), ...Option) = g
-				if cfg.before || cfg.after {
+				if cfg.hook() {
 					do = s
 				}
 				do(newText, func() { f(t, g, s) }, newOpts...)
@@ -161,21 +272,41 @@ func New(text string, opts ...Option) Suite {
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
-// Seed, Report
+// Seed, Report, FailOnFocus
+// Labels, Filter, Timeout
 func Run(t *testing.T, text string, f func(*testing.T, G, S), opts ...Option) bool {
 	t.Helper()
 	cfg := options(opts).apply()
+	if err := cfg.validate(targetRun, []string{text}); err != nil {
+		t.Error(err)
+		return false
+	}
+	cfg.locate()
 	n := &node{
-		text:  []string{text},
-		seed:  defaultZero64(cfg.seed, time.Now().Unix()),
-		order: cfg.order.or(orderSequential),
-		scope: cfg.scope.or(scopeLocal),
-		nest:  cfg.nest.or(nestOff),
-		pend:  cfg.pend,
-		focus: cfg.focus,
+		text:     []string{text},
+		location: cfg.location,
+		seed:     defaultZero64(cfg.seed, time.Now().Unix()),
+		order:    cfg.order.or(orderSequential),
+		scope:    cfg.scope.or(scopeLocal),
+		nest:     cfg.nest.or(nestOff),
+		pend:     cfg.pend,
+		focus:    cfg.focus,
+		labels:   mergeLabels(cfg.labels),
+		timeout:  cfg.timeout,
+	}
+	match, err := newFilter(cfg.filter, os.Getenv(envLabels))
+	if err != nil {
+		t.Error("Invalid label filter:", err)
+		return false
 	}
 	report := cfg.report
-	plan := n.parse(f)
+	plan, focused, errs := n.parse(f, match)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			t.Error(err)
+		}
+		return false
+	}
 
 	var specs chan Spec
 	if report != nil {
@@ -192,93 +323,325 @@ func Run(t *testing.T, text string, f func(*testing.T, G, S), opts ...Option) bo
 		}()
 	}
 
-	return n.run(t, func(t *testing.T, n node) {
+	groups := newGroups(*n, plan.HasFocus)
+
+	failFocus := plan.HasFocus && (cfg.failOnFocus || envBool(envFailOnFocus))
+	if failFocus {
+		defer func() {
+			t.Helper()
+			t.Errorf("Focus is not permitted, but the suite contains focused specs:\n%s",
+				strings.Join(focused, "\n"))
+		}()
+	}
+
+	ok := n.run(t, func(t *testing.T, n node) {
 		t.Helper()
 		buffer := &bytes.Buffer{}
+		var start time.Time
+		var times durations
+		fails := &failures{}
+		panics := &panics{failures: fails}
+		loc := append([]int(nil), n.loc...)
 		defer func() {
 			if specs == nil {
 				return
 			}
+			var duration time.Duration
+			if !start.IsZero() {
+				duration = time.Since(start)
+			}
 			specs <- Spec{
-				Text:     n.text,
-				Failed:   t.Failed(),
-				Skipped:  t.Skipped(),
-				Focused:  n.focus,
-				Parallel: n.order == orderParallel,
-				Out:      buffer,
+				Text:           n.text,
+				Failed:         t.Failed(),
+				Skipped:        t.Skipped(),
+				Focused:        n.focus,
+				Parallel:       n.order == orderParallel,
+				Labels:         n.labels,
+				Location:       n.location,
+				Index:          loc,
+				Out:            buffer,
+				Start:          start,
+				Duration:       duration,
+				BeforeDuration: times.before,
+				BodyDuration:   times.body,
+				AfterDuration:  times.after,
+				Panic:          panics.get(),
 			}
 		}()
 		switch {
-		case n.pend, plan.HasFocus && !n.focus:
+		case n.skip(plan.HasFocus):
 			t.SkipNow()
 		case n.order == orderParallel:
 			t.Parallel()
 		}
+		start = time.Now()
+
+		// The spec runs in a separate goroutine, so that failures reported by
+		// this package are reported from the test goroutine after the spec
+		// completes, with the location of the suite.
+		<-async(func() {
+			ctx, cancel := newContext(n.text)
+			defer cancel()
+			tm := newTimer(ctx, cancel, n.timeout)
+			if tm != nil {
+				ctx = tm
+			}
 
-		var spec, group func()
-		hooks := newHooks()
-		group = func() {}
-
-		f(t, func(_ string, f func(), _ ...Option) {
-			switch {
-			case len(n.loc) == 1, n.loc[0] > 0:
-				n.loc[0]--
-			case n.loc[0] == 0:
-				group = func() {
-					n.loc = n.loc[1:]
-					hooks.next()
-					group = func() {}
-					f()
-					group()
+			var spec, group func()
+			hooks := newHooks(panics, fails, tm)
+			group = func() {}
+			defer hooks.end(t, groups, loc)
+
+			defer panics.recover(t, "G", 0)
+			f(t, func(_ string, f func(), _ ...Option) {
+				switch {
+				case len(n.loc) == 1, n.loc[0] > 0:
+					n.loc[0]--
+				case n.loc[0] == 0:
+					group = func() {
+						n.loc = n.loc[1:]
+						hooks.next()
+						group = func() {}
+						panics.guard(t, "G", hooks.last.level, f)
+						group()
+					}
+					n.loc[0]--
 				}
-				n.loc[0]--
-			}
-		}, func(_ string, f func(), opts ...Option) {
-			cfg := options(opts).apply()
-			switch {
-			case cfg.out != nil:
-				cfg.out(buffer)
-			case cfg.before:
-				hooks.before(f)
-			case cfg.after:
-				hooks.after(f)
-			case spec != nil:
-			case len(n.loc) > 1, n.loc[0] > 0:
-				n.loc[0]--
-			default:
-				spec = f
+			}, func(_ string, f func(), opts ...Option) {
+				cfg := options(opts).apply()
+				switch {
+				case cfg.out != nil:
+					cfg.out(buffer)
+				case cfg.ctx != nil:
+					cfg.ctx(ctx)
+				case cfg.before:
+					hooks.before(f)
+				case cfg.after:
+					hooks.after(f)
+				case cfg.deferred:
+					hooks.deferred(f)
+				case cfg.justBefore:
+					hooks.justBefore(f)
+				case cfg.around != nil:
+					hooks.around(cfg.around)
+				case cfg.beforeAll:
+					hooks.beforeAll(f)
+				case cfg.afterAll:
+					hooks.afterAll(f)
+				case spec != nil:
+				case len(n.loc) > 1, n.loc[0] > 0:
+					n.loc[0]--
+				default:
+					spec = f
+				}
+			})
+			group()
+
+			if spec == nil {
+				fails.fatal(t, "Failed to locate spec.")
 			}
+			hooks.groups(groups, loc)
+			hooks.run(t, spec, &times)
 		})
-		group()
-
-		if spec == nil {
-			t.Fatal("Failed to locate spec.")
-		}
-		hooks.run(t, spec)
+		fails.report(t)
 	})
+	return ok && !failFocus
 }
 
 type specHooks struct {
 	first, last *specHook
+	panics      *panics
+	failures    *failures
+	timer       *timer
 }
 
 type specHook struct {
-	before, after []func()
-	next          *specHook
+	before, after       []func()
+	justBefore          []func()
+	beforeAll, afterAll []func()
+	around              []func(func())
+	deferred            []func()
+	mu                  sync.Mutex
+	group               *group
+	level               int
+	next                *specHook
 }
 
-func newHooks() specHooks {
+func newHooks(p *panics, f *failures, tm *timer) specHooks {
 	h := &specHook{}
-	return specHooks{first: h, last: h}
+	return specHooks{first: h, last: h, panics: p, failures: f, timer: tm}
+}
+
+func (s specHooks) run(t *testing.T, spec func(), d *durations) {
+	t.Helper()
+	defer s.cleanup(t, &d.after)
+	var all time.Duration
+	for h := s.first; h != nil; h = h.next {
+		s.untimed().call(t, &all, "BeforeAll", h.level, s.start(t, h))
+	}
+	s.level(t, s.first, func() {
+		t.Helper()
+		for h := s.first; h != nil; h = h.next {
+			s.call(t, &d.before, "JustBefore", h.level, h.justBefore...)
+		}
+		s.call(t, &d.body, "S", s.last.level, spec)
+	}, d)
+}
+
+// level runs the hooks at the provided level around the hooks at all inner
+// levels and the spec itself.
+func (s specHooks) level(t *testing.T, h *specHook, spec func(), d *durations) {
+	t.Helper()
+	s.wrap(t, h.level, h.around, func() {
+		t.Helper()
+		defer s.call(t, &d.after, "After", h.level, h.after...)
+		s.call(t, &d.before, "Before", h.level, h.before...)
+		if h.next == nil {
+			spec()
+			return
+		}
+		s.level(t, h.next, spec, d)
+	})
+}
+
+// wrap runs f wrapped in each of the provided Around hooks, such that the
+// first hook is the outermost.
+// f runs in a separate goroutine so that each hook may continue after f
+// exits via runtime.Goexit (e.g., t.FailNow or t.SkipNow). In that case, the
+// calling goroutine exits once the hook returns.
+// Each hook must call run exactly once, or the spec fails.
+func (s specHooks) wrap(t *testing.T, level int, hooks []func(func()), f func()) {
+	t.Helper()
+	if len(hooks) == 0 {
+		f()
+		return
+	}
+	called, exited := false, false
+	s.panics.guard(t, "Around", level, func() {
+		hooks[0](func() {
+			t.Helper()
+			if called {
+				s.failures.fatal(t, "Around hook called run more than once.")
+			}
+			called = true
+			exited = !<-async(func() {
+				t.Helper()
+				s.wrap(t, level, hooks[1:], f)
+			})
+		})
+	})
+	if exited {
+		runtime.Goexit()
+	}
+	if !called {
+		s.failures.fatal(t, "Around hook did not call run.")
+	}
+}
+
+// call runs each function as the provided phase of the spec, recording its
+// duration and any panic.
+func (s specHooks) call(t *testing.T, d *time.Duration, phase string, level int, fs ...func()) {
+	t.Helper()
+	start := time.Now()
+	defer func() {
+		*d += time.Since(start)
+	}()
+	for _, f := range fs {
+		s.guard(t, phase, level, f)
+	}
+}
+
+// guard runs f as the provided phase of the spec, recording any panic.
+// If the spec has an unexpired Timeout, f runs in a separate goroutine.
+// When the timeout expires first, the spec fails, f is abandoned, and the
+// calling goroutine exits so that the remaining After hooks, deferred
+// functions, and AfterAll hooks run immediately, without a timeout.
+func (s specHooks) guard(t *testing.T, phase string, level int, f func()) {
+	t.Helper()
+	if s.timer.expired() {
+		s.panics.guard(t, phase, level, f)
+		return
+	}
+	timeout, pause := s.timer.run()
+	defer pause()
+	done := async(func() {
+		t.Helper()
+		s.panics.guard(t, phase, level, func() {
+			defer s.timer.abandon()
+			f()
+		})
+	})
+	select {
+	case returned := <-done:
+		if !returned {
+			runtime.Goexit()
+		}
+	case <-timeout:
+		if elapsed, ok := s.timer.expire(); ok {
+			s.failures.errorf(t, "Spec %s timed out after %s.", t.Name(), elapsed)
+		}
+		t.FailNow()
+	}
+}
+
+// untimed returns hooks that run without a timeout.
+// BeforeAll and AfterAll hooks are untimed, since they run once for the
+// whole group and hold the group until they complete.
+func (s specHooks) untimed() specHooks {
+	s.timer = nil
+	return s
+}
+
+// async runs f in a new goroutine. The returned channel receives true if f
+// returns normally and false if f exits via runtime.Goexit.
+func async(f func()) <-chan bool {
+	done := make(chan bool, 1)
+	go func() {
+		returned := false
+		defer func() {
+			done <- returned
+		}()
+		f()
+		returned = true
+	}()
+	return done
+}
+
+// start returns a function that runs the BeforeAll hooks at the provided
+// level, if they have not already run for the group.
+func (s specHooks) start(t *testing.T, h *specHook) func() {
+	return func() {
+		t.Helper()
+		if !h.group.start(t, h.beforeAll...) {
+			s.failures.fatal(t, "BeforeAll failed for group.")
+		}
+	}
 }
 
-func (s specHooks) run(t *testing.T, spec func()) {
+// end completes the spec in each group that contains it. The AfterAll hooks
+// of each group run, innermost first, if the group has started and this is
+// the last spec in the group to complete.
+// It must be deferred before the suite is evaluated, so that the spec
+// completes even if it fails before its hooks run. In that case, only the
+// AfterAll hooks defined before the failure run.
+func (s specHooks) end(t *testing.T, gs groups, loc []int) {
 	t.Helper()
+	var d time.Duration
+	var levels []*specHook
 	for h := s.first; h != nil; h = h.next {
-		defer run(t, h.after...)
-		run(t, h.before...)
+		levels = append(levels, h)
+	}
+	for i := range loc {
+		var fs []func()
+		if i < len(levels) {
+			fs = levels[i].afterAll
+		}
+		g := gs.get(loc[:i])
+		defer s.untimed().call(t, &d, "AfterAll", i, func() {
+			t.Helper()
+			g.end(t, fs...)
+		})
 	}
-	run(t, spec)
 }
 
 func (s specHooks) before(f func()) {
@@ -289,8 +652,52 @@ func (s specHooks) after(f func()) {
 	s.last.after = append(s.last.after, f)
 }
 
+// deferred registers a function to run after the spec. It may be called by a
+// spec that is abandoned after a Timeout, so the functions are locked.
+func (s specHooks) deferred(f func()) {
+	s.first.mu.Lock()
+	defer s.first.mu.Unlock()
+	s.first.deferred = append(s.first.deferred, f)
+}
+
+// cleanup runs each deferred function in reverse order, such that all of them
+// run even if one exits via runtime.Goexit.
+func (s specHooks) cleanup(t *testing.T, d *time.Duration) {
+	t.Helper()
+	s.first.mu.Lock()
+	fs := s.first.deferred
+	s.first.mu.Unlock()
+	for _, f := range fs {
+		defer s.call(t, d, "Defer", s.last.level, f)
+	}
+}
+
+func (s specHooks) justBefore(f func()) {
+	s.last.justBefore = append(s.last.justBefore, f)
+}
+
+func (s specHooks) around(f func(func())) {
+	s.last.around = append(s.last.around, f)
+}
+
+func (s specHooks) beforeAll(f func()) {
+	s.last.beforeAll = append(s.last.beforeAll, f)
+}
+
+func (s specHooks) afterAll(f func()) {
+	s.last.afterAll = append(s.last.afterAll, f)
+}
+
+// groups associates each level of hooks with the state of the group at that
+// level, given the location of the spec.
+func (s specHooks) groups(gs groups, loc []int) {
+	for h, i := s.first, 0; h != nil; h, i = h.next, i+1 {
+		h.group = gs.get(loc[:i])
+	}
+}
+
 func (s *specHooks) next() {
-	s.last.next = &specHook{}
+	s.last.next = &specHook{level: s.last.level + 1}
 	s.last = s.last.next
 }
 
@@ -301,6 +708,93 @@ func run(t *testing.T, fs ...func()) {
 	}
 }
 
+// timer tracks the Timeout of a spec. Only time spent in timed phases counts
+// toward the timeout, so the deadline moves forward while untimed hooks run.
+// The timer is also the context of the spec, and the context is cancelled
+// when the timer expires.
+type timer struct {
+	context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+	mu      sync.Mutex
+	used    time.Duration
+	since   time.Time
+	done    bool
+}
+
+// newTimer returns a timer that wraps the provided context, or nil if the
+// timeout is not positive.
+func newTimer(ctx context.Context, cancel context.CancelFunc, timeout time.Duration) *timer {
+	if timeout <= 0 {
+		return nil
+	}
+	return &timer{Context: ctx, cancel: cancel, timeout: timeout}
+}
+
+// Deadline returns the time that the timer will expire if the spec runs
+// without any further untimed hooks.
+func (tm *timer) Deadline() (time.Time, bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	since := tm.since
+	if since.IsZero() {
+		since = time.Now()
+	}
+	return since.Add(tm.timeout - tm.used), true
+}
+
+// run counts time toward the timeout until pause is called, and returns a
+// channel that receives when the timeout is reached.
+func (tm *timer) run() (timeout <-chan time.Time, pause func()) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.since = time.Now()
+	clock := time.NewTimer(tm.timeout - tm.used)
+	return clock.C, func() {
+		clock.Stop()
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+		tm.used += time.Since(tm.since)
+		tm.since = time.Time{}
+	}
+}
+
+// expired returns true if the timer has expired or if there is no timer.
+func (tm *timer) expired() bool {
+	if tm == nil {
+		return true
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.done
+}
+
+// expire expires the timer and cancels the context of the spec. It returns
+// the time counted toward the timeout, or false if the timer already expired.
+func (tm *timer) expire() (time.Duration, bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.done {
+		return 0, false
+	}
+	tm.done = true
+	tm.cancel()
+	return tm.used + time.Since(tm.since), true
+}
+
+// abandon recovers a panic from a function that was abandoned after the
+// timer expired, since the spec may have already completed.
+// It must be deferred by the abandoned function.
+func (tm *timer) abandon() {
+	if tm.expired() {
+		recover()
+	}
+}
+
+type durations struct {
+	before, body, after time.Duration
+}
+
 // Pend skips all specs in the top-level group.
 //
 // All Options are ignored.
@@ -315,32 +809,84 @@ func Pend(t *testing.T, text string, f func(*testing.T, G, S), _ ...Option) bool
 // Valid Options:
 // Sequential, Random, Reverse, Parallel
 // Local, Global, Flat, Nested
-// Seed, Report
+// Seed, Report, FailOnFocus
+// Labels, Filter, Timeout
 func Focus(t *testing.T, text string, f func(*testing.T, G, S), opts ...Option) bool {
 	t.Helper()
 	return Run(t, text, f, append(opts, func(c *config) { c.focus = true })...)
 }
 
 // A Plan provides a Reporter with information about a suite.
+//
+// Excluded counts the specs that are skipped because they do not match the
+// label Filter. Labels contains every label in the suite, sorted.
+// Nodes contains the top-level groups and specs in the suite, in the order
+// they are defined.
 type Plan struct {
 	Text      string
 	Total     int
 	Pending   int
 	Focused   int
+	Excluded  int
 	Seed      int64
 	HasRandom bool
 	HasFocus  bool
+	Labels    []string
+	Nodes     []Node
+}
+
+// A Node describes a group or spec in a suite, as it is defined.
+// Nodes is nil for specs and non-nil for groups, even if they are empty.
+//
+// Order is "sequential", "random", "reverse", or "parallel".
+// Global and Nested correspond to the Global and Nested Options.
+// Excluded is true for specs that do not match the label Filter.
+// All fields include the effects of Options inherited from parent groups.
+// Index is the position of the node in the tree, such that the node is
+// plan.Nodes[Index[0]].Nodes[Index[1]]... and so on. Index matches the Index
+// of the corresponding Spec.
+type Node struct {
+	Text     string
+	Location Location
+	Order    string
+	Global   bool
+	Nested   bool
+	Pending  bool
+	Focused  bool
+	Excluded bool
+	Labels   []string
+	Index    []int
+	Nodes    []Node
 }
 
 // A Spec provides a Reporter with information about a spec immediately after
 // the spec completes.
+//
+// Start and Duration cover the spec and all of its hooks. Start is zero for
+// specs that are skipped before they begin, such as pending specs.
+// BeforeDuration covers the Before and JustBefore hooks, BodyDuration covers
+// the spec itself, and AfterDuration covers the After hooks and deferred
+// functions. Time spent in BeforeAll, AfterAll, and Around hooks is only
+// included in Duration.
+// Location is the file and line where the spec is defined.
+// Index is the position of the spec in Plan.Nodes.
+// Panic describes the first panic in the spec or its hooks, if any.
 type Spec struct {
-	Text     []string
-	Failed   bool
-	Skipped  bool
-	Focused  bool
-	Parallel bool
-	Out      io.Reader
+	Text           []string
+	Failed         bool
+	Skipped        bool
+	Focused        bool
+	Parallel       bool
+	Labels         []string
+	Location       Location
+	Index          []int
+	Out            io.Reader
+	Start          time.Time
+	Duration       time.Duration
+	BeforeDuration time.Duration
+	BodyDuration   time.Duration
+	AfterDuration  time.Duration
+	Panic          *Panic
 }
 
 // A Reporter is provided with information about a suite as it runs.
@@ -1,9 +1,16 @@
 package spec_test
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
@@ -26,6 +33,26 @@ func record(t *testing.T) (s recorder, c func() []string) {
 		}
 }
 
+const envSubprocess = "SPEC_TEST_SUBPROCESS"
+
+// subprocess runs the current test in a new test process with the provided
+// environment, so that the test may fail without failing the current process.
+// The test must call inSubprocess to determine which process it is in.
+func subprocess(t *testing.T, env ...string) string {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(append(os.Environ(), envSubprocess+"="+t.Name()), env...)
+	out, err := cmd.CombinedOutput()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		t.Fatal("Failed to run subprocess:", err)
+	}
+	return string(out)
+}
+
+func inSubprocess(t *testing.T) bool {
+	return os.Getenv(envSubprocess) == t.Name()
+}
+
 func specTestCases(t *testing.T, when spec.G, it spec.S, s recorder) {
 	it.Before(s(t, "Before"))
 	it.After(s(t, "After"))
@@ -364,6 +391,530 @@ func TestSuiteAfter(t *testing.T) {
 	}
 }
 
+func TestSJustBefore(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.JustBefore(s(t, "Run.JustBefore"))
+		it.Before(s(t, "Run.Before"))
+		it("Run.S", s(t, "Run.S"))
+		when("Run.G", func() {
+			it.JustBefore(s(t, "Run.G.JustBefore"))
+			it.Before(s(t, "Run.G.Before"))
+			it("Run.G.S", s(t, "Run.G.S"))
+		})
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/Run.S->Run.Before",
+		"Run/Run.S->Run.JustBefore",
+		"Run/Run.S->Run.S",
+
+		"Run/Run.G/Run.G.S->Run.Before",
+		"Run/Run.G/Run.G.S->Run.G.Before",
+		"Run/Run.G/Run.G.S->Run.JustBefore", "Run/Run.G/Run.G.S->Run.G.JustBefore",
+		"Run/Run.G/Run.G.S->Run.G.S",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestSAround(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.Around(func(run func()) {
+			s(t, "Run.Around.1.Start")()
+			run()
+			s(t, "Run.Around.1.End")()
+		})
+		it.Before(s(t, "Run.Before"))
+		it.After(s(t, "Run.After"))
+		it.Around(func(run func()) {
+			s(t, "Run.Around.2.Start")()
+			run()
+			s(t, "Run.Around.2.End")()
+		})
+		when("Run.G", func() {
+			it.Around(func(run func()) {
+				s(t, "Run.G.Around.Start")()
+				run()
+				s(t, "Run.G.Around.End")()
+			})
+			it.Before(s(t, "Run.G.Before"))
+			it("Run.G.S", func() {
+				s(t, "Run.G.S")()
+				t.SkipNow()
+			})
+		})
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/Run.G/Run.G.S->Run.Around.1.Start", "Run/Run.G/Run.G.S->Run.Around.2.Start",
+		"Run/Run.G/Run.G.S->Run.Before",
+		"Run/Run.G/Run.G.S->Run.G.Around.Start",
+		"Run/Run.G/Run.G.S->Run.G.Before",
+		"Run/Run.G/Run.G.S->Run.G.S",
+		"Run/Run.G/Run.G.S->Run.G.Around.End",
+		"Run/Run.G/Run.G.S->Run.After",
+		"Run/Run.G/Run.G.S->Run.Around.2.End", "Run/Run.G/Run.G.S->Run.Around.1.End",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestSAroundRun(t *testing.T) {
+	if inSubprocess(t) {
+		spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			when("G.Skip", func() {
+				it.Around(func(run func()) {})
+				it("S", func() { t.Fatal("S ran.") })
+			})
+			when("G.Twice", func() {
+				it.Around(func(run func()) {
+					run()
+					run()
+				})
+				it("S", func() { fmt.Println("S ran:", t.Name()) })
+			})
+		})
+		return
+	}
+
+	out := subprocess(t)
+	for _, re := range []string{
+		`(?m)^ +spec_test.go:[0-9]+: Around hook did not call run\.$`,
+		`(?m)^ +--- FAIL: TestSAroundRun/Run/G.Skip/S `,
+		`(?m)^ +spec_test.go:[0-9]+: Around hook called run more than once\.$`,
+		`(?m)^ +--- FAIL: TestSAroundRun/Run/G.Twice/S `,
+	} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Fatalf("Output does not match %s:\n%s", re, out)
+		}
+	}
+	if strings.Contains(out, "S ran.") || strings.Count(out, "S ran: ") != 1 {
+		t.Fatal("Incorrect spec runs:", out)
+	}
+}
+
+func TestSBeforeAllAfterAll(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.Before(s(t, "Run.Before"))
+		it.BeforeAll(s(t, "Run.BeforeAll"))
+		it.AfterAll(s(t, "Run.AfterAll"))
+		it("Run.S.1", s(t, "Run.S.1"))
+		when("Run.G", func() {
+			it.BeforeAll(s(t, "Run.G.BeforeAll"))
+			it.AfterAll(s(t, "Run.G.AfterAll"))
+			it("Run.G.S.1", s(t, "Run.G.S.1"))
+			it("Run.G.S.2", s(t, "Run.G.S.2"))
+		})
+		it("Run.S.2", s(t, "Run.S.2"))
+		it.Pend("Run.S.Pend", s(t, "Run.S.Pend"))
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/Run.S.1->Run.BeforeAll",
+		"Run/Run.S.1->Run.Before",
+		"Run/Run.S.1->Run.S.1",
+
+		"Run/Run.G/Run.G.S.1->Run.G.BeforeAll",
+		"Run/Run.G/Run.G.S.1->Run.Before",
+		"Run/Run.G/Run.G.S.1->Run.G.S.1",
+
+		"Run/Run.G/Run.G.S.2->Run.Before",
+		"Run/Run.G/Run.G.S.2->Run.G.S.2",
+		"Run/Run.G/Run.G.S.2->Run.G.AfterAll",
+
+		"Run/Run.S.2->Run.Before",
+		"Run/Run.S.2->Run.S.2",
+		"Run/Run.S.2->Run.AfterAll",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestSBeforeAllParallel(t *testing.T) {
+	var mu sync.Mutex
+	var ready bool
+	var beforeAll, afterAll, specs int
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.BeforeAll(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			beforeAll++
+			ready = true
+		})
+		it.AfterAll(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			afterAll++
+			ready = false
+		})
+		for i := 0; i < 5; i++ {
+			it("Run.S", func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if !ready {
+					t.Error("BeforeAll did not run first.")
+				}
+				specs++
+			})
+		}
+	}, spec.Parallel())
+
+	if beforeAll != 1 || afterAll != 1 || specs != 5 {
+		t.Fatal("Incorrect counts:", beforeAll, afterAll, specs)
+	}
+}
+
+func TestSuiteBeforeAllAfterAll(t *testing.T) {
+	s, calls := record(t)
+
+	suite := spec.New("Suite")
+	suite.BeforeAll(func(t *testing.T) {
+		s(t, "BeforeAll")()
+	})
+	suite.AfterAll(func(t *testing.T) {
+		s(t, "AfterAll")()
+	})
+	suite("Top.1", func(t *testing.T, when spec.G, it spec.S) {
+		it("Top.1.S", s(t, "Top.1.S"))
+	})
+	suite("Top.2", func(t *testing.T, when spec.G, it spec.S) {
+		it("Top.2.S", s(t, "Top.2.S"))
+	})
+	suite.Run(t)
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Suite/Top.1/Top.1.S->BeforeAll",
+		"Suite/Top.1/Top.1.S->Top.1.S",
+		"Suite/Top.2/Top.2.S->Top.2.S",
+		"Suite/Top.2/Top.2.S->AfterAll",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestSBeforeAllAfterAllFailure(t *testing.T) {
+	if inSubprocess(t) {
+		spec.Run(t, "BeforeAll", func(t *testing.T, when spec.G, it spec.S) {
+			it.BeforeAll(func() { t.Fatal("BeforeAll failed.") })
+			it.AfterAll(func() { fmt.Println("BeforeAll.AfterAll ran.") })
+			when("G", func() {
+				it.BeforeAll(func() { fmt.Println("BeforeAll.G.BeforeAll ran.") })
+				it.AfterAll(func() { fmt.Println("BeforeAll.G.AfterAll ran.") })
+				it("S", func() {})
+			})
+		})
+		spec.Run(t, "Group", func(t *testing.T, when spec.G, it spec.S) {
+			it.AfterAll(func() { fmt.Println("Group.AfterAll ran.") })
+			it("S", func() {})
+			when("G", func() {
+				it("S", func() {})
+				if t != nil {
+					t.Fatal("G failed.")
+				}
+			})
+		})
+		return
+	}
+
+	out := subprocess(t)
+	for _, re := range []string{
+		`(?m)^ +spec_test.go:[0-9]+: BeforeAll failed\.$`,
+		`(?m)^BeforeAll.AfterAll ran\.$`,
+		`(?m)^ +--- FAIL: TestSBeforeAllAfterAllFailure/BeforeAll/G/S `,
+		`(?m)^ +spec_test.go:[0-9]+: G failed\.$`,
+		`(?m)^Group.AfterAll ran\.$`,
+		`(?m)^ +--- FAIL: TestSBeforeAllAfterAllFailure/Group/G/S `,
+	} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Fatalf("Output does not match %s:\n%s", re, out)
+		}
+	}
+	if strings.Contains(out, "BeforeAll.G.") || strings.Count(out, "AfterAll ran.") != 2 {
+		t.Fatal("Incorrect hook runs:", out)
+	}
+}
+
+func TestEach(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		spec.Each(it, "adds %d and %d", [][]any{{1, 2}, {3, 4}, {1, 2}}, func(row []any) {
+			s(t, fmt.Sprint(row...))()
+		})
+		spec.Each(when, "with %s", []string{"G.1", "G.2"}, func(row string) {
+			it.Before(s(t, row+".Before"))
+			it(row+".S", s(t, row+".S"))
+		}, spec.Reverse())
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/adds_1_and_2->1 2",
+		"Run/adds_3_and_4->3 4",
+		"Run/adds_1_and_2_#2->1 2",
+
+		"Run/with_G.1/G.1.S->G.1.Before", "Run/with_G.1/G.1.S->G.1.S",
+		"Run/with_G.2/G.2.S->G.2.Before", "Run/with_G.2/G.2.S->G.2.S",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestTable(t *testing.T) {
+	s, calls := record(t)
+	reporter := &testReporter{}
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		spec.Table(it, "S.%d", []spec.Row[int]{
+			{Value: 1},
+			spec.FocusRow(2),
+			spec.PendRow(3),
+		}, func(row int) {
+			s(t, fmt.Sprint("S.", row))()
+		})
+		spec.Table(when, "G.%d", []spec.Row[int]{
+			{Value: 1},
+			spec.FocusRow(2),
+		}, func(row int) {
+			it("S", s(t, fmt.Sprint("G.", row, ".S")))
+		})
+	}, spec.Report(reporter))
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/S.2->S.2",
+		"Run/G.2/S->G.2.S",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+	if plan := reporter.StartPlan; !plan.HasFocus || plan.Focused != 2 || plan.Pending != 1 {
+		t.Fatal("Incorrect plan:", plan)
+	}
+}
+
+func TestShared(t *testing.T) {
+	s, calls := record(t)
+
+	named := spec.Shared("a named thing", func(t *testing.T, when spec.G, it spec.S, subject func() string) {
+		it("has a name", func() {
+			s(t, subject())()
+		})
+	})
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		when("First", func() {
+			named.Behaves(t, when, it, func() string { return "First.Name" })
+		})
+		when("Second", func() {
+			named.Behaves(t, when, it, func() string { return "Second.Name" })
+		})
+		when("Third", func() {
+			named.Pend(t, when, it, func() string { return "Third.Name" })
+		})
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/First/behaves_like_a_named_thing/has_a_name->First.Name",
+		"Run/Second/behaves_like_a_named_thing/has_a_name->Second.Name",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+func TestLet(t *testing.T) {
+	s, calls := record(t)
+	var evaluated int
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		value := spec.Let(it, func() string {
+			evaluated++
+			return "Run.Let"
+		})
+		it.Before(func() {
+			s(t, value.Get())()
+		})
+		it("Run.S", func() {
+			s(t, value.Get())()
+		})
+		when("Run.G", func() {
+			value.Let(func() string {
+				evaluated++
+				return "Run.G.Let"
+			})
+			it("Run.G.S", func() {
+				s(t, value.Get())()
+			})
+		})
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/Run.S->Run.Let", "Run/Run.S->Run.Let",
+		"Run/Run.G/Run.G.S->Run.G.Let", "Run/Run.G/Run.G.S->Run.G.Let",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+	if evaluated != 2 {
+		t.Fatal("Incorrect number of evaluations:", evaluated)
+	}
+}
+
+func TestLetParallel(t *testing.T) {
+	var mu sync.Mutex
+	values := map[string]bool{}
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		value := spec.Let(it, func() string { return t.Name() })
+		for i := 0; i < 5; i++ {
+			it("Run.S", func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if value.Get() != t.Name() {
+					t.Error("Value shared between specs:", value.Get())
+				}
+				values[value.Get()] = true
+			})
+		}
+	}, spec.Parallel())
+
+	if len(values) != 5 {
+		t.Fatal("Incorrect values:", values)
+	}
+}
+
+func TestSDefer(t *testing.T) {
+	s, calls := record(t)
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		it.Before(func() {
+			it.Defer(s(t, "Run.Before.Defer"))
+		})
+		it.After(s(t, "Run.After"))
+		it.AfterAll(s(t, "Run.AfterAll"))
+		when("Run.G", func() {
+			it.Before(func() {
+				it.Defer(s(t, "Run.G.Before.Defer"))
+			})
+			it("Run.G.S", func() {
+				it.Defer(s(t, "Run.G.S.Defer"))
+				s(t, "Run.G.S")()
+				t.SkipNow()
+			})
+		})
+	})
+
+	if !reflect.DeepEqual(calls(), []string{
+		"Run/Run.G/Run.G.S->Run.G.S",
+		"Run/Run.G/Run.G.S->Run.After",
+		"Run/Run.G/Run.G.S->Run.G.S.Defer",
+		"Run/Run.G/Run.G.S->Run.G.Before.Defer",
+		"Run/Run.G/Run.G.S->Run.Before.Defer",
+		"Run/Run.G/Run.G.S->Run.AfterAll",
+	}) {
+		t.Fatal("Incorrect order:", calls())
+	}
+}
+
+type panicReporter struct{}
+
+func (panicReporter) Start(*testing.T, spec.Plan) {}
+
+func (panicReporter) Specs(_ *testing.T, specs <-chan spec.Spec) {
+	for s := range specs {
+		if p := s.Panic; p != nil {
+			fmt.Printf("Panic: %s: %s %d %v\n%s\n", strings.Join(s.Text, "/"), p.Phase, p.Level, p.Value, p.Stack)
+		} else {
+			fmt.Printf("No panic: %s failed=%t\n", strings.Join(s.Text, "/"), s.Failed)
+		}
+	}
+}
+
+func TestPanic(t *testing.T) {
+	if inSubprocess(t) {
+		spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			it("S", func() { panic("S panic") })
+			when("G.Before", func() {
+				it.Before(func() { panic("Before panic") })
+				it.After(func() { fmt.Println("After ran:", t.Name()) })
+				it("S", func() { t.Error("S ran.") })
+			})
+			when("G.Around", func() {
+				when("G", func() {
+					it.Around(func(run func()) { panic("Around panic") })
+					it("S", func() { t.Error("S ran.") })
+				})
+			})
+			when("G.After", func() {
+				it.After(func() { panic("After panic") })
+				it("S", func() {})
+			})
+			when("G.Panic", func() {
+				if t != nil {
+					panic("G panic")
+				}
+				it("S", func() {})
+			})
+			it("S.Pass", func() {})
+		}, spec.Report(panicReporter{}))
+		return
+	}
+
+	out := subprocess(t)
+	for _, re := range []string{
+		`(?m)^Panic: S: S 0 S panic\ngithub.com/sclevine/spec_test.TestPanic.func1.1\(\)\n\t.+/spec_test.go:[0-9]+ `,
+		`(?m)^Panic: G.Before/S: Before 1 Before panic\ngithub.com/sclevine/spec_test.TestPanic.func1.2.1\(\)\n`,
+		`(?m)^After ran: TestPanic/Run/G.Before/S$`,
+		`(?m)^Panic: G.Around/G/S: Around 2 Around panic\n`,
+		`(?m)^Panic: G.After/S: After 1 After panic\n`,
+		`(?m)^Panic: G.Panic/S: G 1 G panic\n`,
+		`(?m)^No panic: S.Pass failed=false$`,
+		`(?m)^ +spec_test.go:[0-9]+: Panic in S \(level 0\): S panic$`,
+		`(?m)^ +--- PASS: TestPanic/Run/S.Pass `,
+	} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Fatalf("Output does not match %s:\n%s", re, out)
+		}
+	}
+	if strings.Contains(out, "S ran.") {
+		t.Fatal("Spec ran after panic:", out)
+	}
+	if strings.Contains(out, "github.com/sclevine/spec.") || strings.Contains(out, "testing.") {
+		t.Fatal("Stack not trimmed:", out)
+	}
+}
+
+func TestPanicParse(t *testing.T) {
+	if inSubprocess(t) {
+		fmt.Println("Run returned:", spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+			when("G.Panic", func() {
+				it("G.Panic.S", func() {})
+				panic("some panic")
+			})
+			when("G", func() {
+				it("G.S", func() { fmt.Println("S ran.") })
+			})
+		}))
+		return
+	}
+
+	out := subprocess(t)
+	for _, re := range []string{
+		`(?m)^ +spec_test.go:[0-9]+: Panic in Run/G.Panic: some panic\n +github.com/sclevine/spec_test.TestPanicParse.func1.1\(\)$`,
+		`(?m)^Run returned: false$`,
+	} {
+		if !regexp.MustCompile(re).MatchString(out) {
+			t.Fatalf("Output does not match %s:\n%s", re, out)
+		}
+	}
+	if strings.Contains(out, "S ran.") || strings.Count(out, "Panic in") != 1 {
+		t.Fatal("Incorrect output:", out)
+	}
+}
+
 func TestSkipAfter(t *testing.T) {
 	s, calls := record(t)
 
@@ -387,6 +938,47 @@ func TestSkipAfter(t *testing.T) {
 	}
 }
 
+func TestContext(t *testing.T) {
+	var parseCtx context.Context
+	var ctxs []context.Context
+
+	spec.Run(t, "Run", func(t *testing.T, when spec.G, it spec.S) {
+		if t == nil {
+			parseCtx = it.Context()
+		}
+		when("Run.G", func() {
+			it.Before(func() {
+				ctxs = append(ctxs, it.Context())
+			})
+			it("Run.G.S", func() {
+				ctx := it.Context()
+				if ctx.Err() != nil {
+					t.Fatal("Context cancelled during spec.")
+				}
+				if !reflect.DeepEqual(spec.TextFromContext(ctx), []string{"Run.G", "Run.G.S"}) {
+					t.Fatal("Incorrect text:", spec.TextFromContext(ctx))
+				}
+				ctxs = append(ctxs, ctx)
+			})
+		})
+		it("Run.S.Timeout", func() {
+			if _, ok := it.Context().Deadline(); !ok {
+				t.Fatal("Missing deadline.")
+			}
+		}, spec.Timeout(time.Minute))
+	})
+
+	if parseCtx != nil {
+		t.Fatal("Context available during parse phase.")
+	}
+	if len(ctxs) != 2 || ctxs[0] != ctxs[1] {
+		t.Fatal("Incorrect contexts:", ctxs)
+	}
+	if ctxs[0].Err() != context.Canceled {
+		t.Fatal("Context not cancelled after spec.")
+	}
+}
+
 func TestSpec(t *testing.T) {
 	spec.Run(t, "spec", func(t *testing.T, when spec.G, it spec.S) {
 		when("something happens", func() {
@@ -0,0 +1,88 @@
+package spec
+
+import "fmt"
+
+// Each defines a spec for each row of a table when passed an S, or a group
+// for each row when passed a G.
+// The text of each spec or group is the row formatted with fmt.Sprintf and
+// the provided format. If a row is a []any, its elements are passed to
+// fmt.Sprintf individually, such that each may have its own verb.
+// Rows that format identically are numbered to keep their text unique.
+// Use Table to focus or pend individual rows.
+//
+// Example:
+//
+//	spec.Each(it, "adds %v and %v", [][]any{{1, 2}, {2, 3}}, func(row []any) {...})
+//
+// Valid Options:
+// The Options valid for the provided S or G
+func Each[F ~func(string, func(), ...Option), T any](do F, format string, rows []T, f func(T), opts ...Option) {
+	table := make([]Row[T], len(rows))
+	for i, row := range rows {
+		table[i] = Row[T]{Value: row}
+	}
+	Table(do, format, table, f, opts...)
+}
+
+// A Row is a row of a table defined with Table.
+// Rows may be focused with FocusRow or pended with PendRow.
+type Row[T any] struct {
+	Value T
+	focus bool
+	pend  bool
+}
+
+// FocusRow focuses the provided row of a table.
+// This skips all specs in the suite except the row and other focused specs.
+func FocusRow[T any](value T) Row[T] {
+	return Row[T]{Value: value, focus: true}
+}
+
+// PendRow skips the provided row of a table.
+func PendRow[T any](value T) Row[T] {
+	return Row[T]{Value: value, pend: true}
+}
+
+// Table behaves like Each, except that individual rows may be focused or
+// pended with FocusRow and PendRow.
+//
+// Example:
+//
+//	spec.Table(it, "adds %+v", []spec.Row[Case]{
+//		{Value: Case{1, 2, 3}},
+//		spec.FocusRow(Case{2, 3, 5}),
+//		spec.PendRow(Case{3, 4, 7}),
+//	}, func(c Case) {...})
+//
+// Valid Options:
+// The Options valid for the provided S or G
+func Table[F ~func(string, func(), ...Option), T any](do F, format string, rows []Row[T], f func(T), opts ...Option) {
+	for i, text := range rowText(format, rows) {
+		row := rows[i]
+		rowOpts := opts
+		switch {
+		case row.pend:
+			rowOpts = []Option{func(c *config) { c.pend = true }}
+		case row.focus:
+			rowOpts = append(opts[:len(opts):len(opts)], func(c *config) { c.focus = true })
+		}
+		do(text, func() { f(row.Value) }, rowOpts...)
+	}
+}
+
+func rowText[T any](format string, rows []Row[T]) []string {
+	texts := make([]string, len(rows))
+	seen := map[string]int{}
+	for i, row := range rows {
+		args := []any{row.Value}
+		if a, ok := any(row.Value).([]any); ok {
+			args = a
+		}
+		text := fmt.Sprintf(format, args...)
+		if seen[text]++; seen[text] > 1 {
+			text = fmt.Sprintf("%s #%d", text, seen[text])
+		}
+		texts[i] = text
+	}
+	return texts
+}